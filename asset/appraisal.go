@@ -0,0 +1,162 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// appraisalKey is the transient map entry carrying the appraisal of a transaction, so
+// that the confidential value is not recorded in the transaction itself
+const appraisalKey = `appraisal`
+
+// Appraisal is the confidential valuation of an asset, which is kept in the implicit
+// private data collection of the org of the appraiser
+type Appraisal struct {
+	ID    int `json:"id"`
+	Value int `json:"value"`
+}
+
+func implicitCollection(mspID string) string {
+	return `_implicit_org_` + mspID
+}
+
+// SetAssetAppraisal stores the appraisal passed in the transient map in the implicit
+// collection of the org of the invoker
+func (s *SmartContract) SetAssetAppraisal(ctx contractapi.TransactionContextInterface, id int) error {
+	exists, err := assetFamily.exists(ctx, id)
+	if err != nil {
+		return fmt.Errorf(`checking asset existence failed - %w`, err)
+	}
+
+	if !exists {
+		return errorf(CodeNotFound, `asset with id %d does not exist`, id)
+	}
+
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	byts, err := transientAppraisal(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	k, err := key(ctx, kindAsset, id)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collection, k, byts); err != nil {
+		return fmt.Errorf(`put private data failed for asset %d - %w`, id, err)
+	}
+
+	return nil
+}
+
+// GetAssetPrivateDetails returns the appraisal of the asset made by the org of the invoker
+func (s *SmartContract) GetAssetPrivateDetails(ctx contractapi.TransactionContextInterface, id int) (*Appraisal, error) {
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := key(ctx, kindAsset, id)
+	if err != nil {
+		return nil, err
+	}
+
+	byts, err := ctx.GetStub().GetPrivateData(collection, k)
+	if err != nil {
+		return nil, fmt.Errorf(`get private data failed for asset %d - %w`, id, err)
+	}
+
+	if byts == nil {
+		return nil, errorf(CodeNotFound, `asset %d has no appraisal in %s`, id, collection)
+	}
+
+	var a Appraisal
+	if err = json.Unmarshal(byts, &a); err != nil {
+		return nil, fmt.Errorf(`unmarshal appraisal failed for asset %d - %w`, id, err)
+	}
+
+	return &a, nil
+}
+
+// VerifyAssetAppraisal reports whether the appraisal passed in the transient map matches
+// the one the given org holds for the asset, which any org can check through its hash
+func (s *SmartContract) VerifyAssetAppraisal(ctx contractapi.TransactionContextInterface, id int, mspID string) (bool, error) {
+	byts, err := transientAppraisal(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	k, err := key(ctx, kindAsset, id)
+	if err != nil {
+		return false, err
+	}
+
+	collection := implicitCollection(mspID)
+	hash, err := ctx.GetStub().GetPrivateDataHash(collection, k)
+	if err != nil {
+		return false, fmt.Errorf(`get private data hash failed for asset %d - %w`, id, err)
+	}
+
+	if hash == nil {
+		return false, errorf(CodeNotFound, `asset %d has no appraisal in %s`, id, collection)
+	}
+
+	sum := sha256.Sum256(byts)
+	return bytes.Equal(sum[:], hash), nil
+}
+
+// transientAppraisal returns the canonical encoding of the appraisal in the transient
+// map, which is both stored and hashed for verification
+func transientAppraisal(ctx contractapi.TransactionContextInterface, id int) ([]byte, error) {
+	tm, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf(`get transient map failed - %w`, err)
+	}
+
+	in, ok := tm[appraisalKey]
+	if !ok {
+		return nil, errorf(CodeInvalid, `%s is missing in the transient map`, appraisalKey)
+	}
+
+	var a Appraisal
+	if err = json.Unmarshal(in, &a); err != nil {
+		return nil, errorf(CodeInvalid, `%s is not a JSON appraisal - %w`, appraisalKey, err)
+	}
+
+	if a.Value < 0 {
+		return nil, errorf(CodeInvalid, `appraisal value %d must not be negative`, a.Value)
+	}
+	a.ID = id
+
+	byts, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf(`marshal appraisal failed - %w`, err)
+	}
+
+	return byts, nil
+}
+
+// clientOrgCollection returns the implicit collection of the org of the invoker. Fabric only
+// lets member peers of the org endorse writes to it, hence no peer of another org may
+// disclose or alter its private data, whichever org the chaincode service runs for.
+func clientOrgCollection(ctx contractapi.TransactionContextInterface) (string, error) {
+	ci := ctx.GetClientIdentity()
+	if ci == nil {
+		return ``, fmt.Errorf(`client identity of the transaction is not available`)
+	}
+
+	clientMSP, err := ci.GetMSPID()
+	if err != nil {
+		return ``, fmt.Errorf(`get msp id of the client failed - %w`, err)
+	}
+
+	return implicitCollection(clientMSP), nil
+}
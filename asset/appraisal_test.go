@@ -0,0 +1,86 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"os"
+	"testing"
+)
+
+func TestSmartContractAssetAppraisal(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	stub.TransientMap = map[string][]byte{appraisalKey: []byte(`{"value":700}`)}
+	if res := stub.invoke(`2`, "SetAssetAppraisal", "1"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`3`, "GetAssetPrivateDetails", "1")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if in := `{"id":1,"value":700}`; string(res.Payload) != in {
+		t.Fatalf(errExpect, in, res.Payload)
+	}
+
+	for _, tc := range []struct {
+		appraisal, match string
+	}{
+		{appraisal: `{"value":700}`, match: `true`},
+		{appraisal: `{"value":701}`, match: `false`},
+	} {
+		stub.TransientMap = map[string][]byte{appraisalKey: []byte(tc.appraisal)}
+		res = stub.invoke(`4`, "VerifyAssetAppraisal", "1", testMSP)
+		if res.Status != shim.OK {
+			t.Fatalf(errOK, res.Status, res.Message)
+		}
+
+		if string(res.Payload) != tc.match {
+			t.Fatalf(errExpect, tc.match, res.Payload)
+		}
+	}
+}
+
+func TestSmartContractAssetAppraisalOfClientOrg(t *testing.T) {
+	// the chaincode service is shared by the orgs, hence the peer org is not configured
+	if _, ok := os.LookupEnv("CORE_PEER_LOCALMSPID"); ok {
+		t.Skip("the test requires the peer org to be unset")
+	}
+
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = carolIdentity
+	stub.TransientMap = map[string][]byte{appraisalKey: []byte(`{"value":700}`)}
+	if res := stub.invoke(`2`, "SetAssetAppraisal", "1"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if len(stub.PvtState[implicitCollection(buyerMSP)]) != 1 || len(stub.PvtState[implicitCollection(testMSP)]) != 0 {
+		t.Fatalf(`appraisal should be kept in the implicit collection of the org of the client`)
+	}
+
+	// the appraisal of an org is not visible to clients of other orgs
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`3`, "GetAssetPrivateDetails", "1"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+}
+
+func TestSmartContractAssetAppraisalMalformed(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.TransientMap = map[string][]byte{appraisalKey: []byte(`{"value":`)}
+	if res := stub.invoke(`2`, "SetAssetAppraisal", "1"); DecodeError(res.Message).Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res.Message)
+	}
+}
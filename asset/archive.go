@@ -0,0 +1,200 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// archiveKey is the object type of the deleted records, keyed by the kind and id of the record
+// and by the time and transaction of its deletion, so that every deletion of an id is kept in
+// the order of the deletions
+const archiveKey = `archive~kind~id~deletedAt~txid`
+
+// docArchived tags the archived records, so that the rich queries of the kinds do not match them
+const docArchived = `archived`
+
+// ArchivedRecord is a deleted record kept for audits along with its deletion. Record
+// holds the decoded record of the asset kind as it was when deleted.
+type ArchivedRecord struct {
+	DeletedAt string      `json:"deletedAt"`
+	DeletedBy string      `json:"deletedBy"`
+	DocType   string      `json:"docType"`
+	ID        int         `json:"id"`
+	Kind      string      `json:"kind"`
+	Record    interface{} `json:"record"`
+}
+
+// getArchived returns the record of the given id as it was when last deleted, along with the
+// key of its deletion
+func (f family[T, P]) getArchived(ctx contractapi.TransactionContextInterface, id int) (*T, string, error) {
+	itr, err := ctx.GetStub().GetStateByPartialCompositeKey(archiveKey, []string{f.store.kind, strconv.Itoa(id)})
+	if err != nil {
+		return nil, ``, fmt.Errorf(`get archive failed for %s %d - %w`, f.store.kind, id, err)
+	}
+	defer itr.Close()
+
+	// the latest deletion comes last, as the timestamps of the keys are of fixed width
+	var k string
+	var byts []byte
+	for itr.HasNext() {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, ``, fmt.Errorf(`iterating next archive failed for %s %d - %w`, f.store.kind, id, err)
+		}
+		k, byts = res.Key, res.Value
+	}
+
+	if byts == nil {
+		return nil, ``, errorf(CodeNotFound, `archived %s with id %d does not exist`, f.store.kind, id)
+	}
+
+	// the record is decoded into the type of the kind the interface field points to
+	r := new(T)
+	a := ArchivedRecord{Record: r}
+	if err = json.Unmarshal(byts, &a); err != nil {
+		return nil, ``, fmt.Errorf(`unmarshal archive failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return r, k, nil
+}
+
+// archive keeps a record deleted by the invoker next to any earlier deletion of the same id
+func (f family[T, P]) archive(ctx contractapi.TransactionContextInterface, r P) error {
+	at, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	by, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	k, err := ctx.GetStub().CreateCompositeKey(archiveKey, []string{f.store.kind, strconv.Itoa(r.id()), at, ctx.GetStub().GetTxID()})
+	if err != nil {
+		return fmt.Errorf(`create archive key failed for %s %d - %w`, f.store.kind, r.id(), err)
+	}
+
+	byts, err := json.Marshal(ArchivedRecord{
+		DeletedAt: at,
+		DeletedBy: by,
+		DocType:   docArchived,
+		ID:        r.id(),
+		Kind:      f.store.kind,
+		Record:    r,
+	})
+	if err != nil {
+		return fmt.Errorf(`marshal archive failed for %s %d - %w`, f.store.kind, r.id(), err)
+	}
+
+	if err = ctx.GetStub().PutState(k, byts); err != nil {
+		return fmt.Errorf(`put archive failed for %s %d - %w`, f.store.kind, r.id(), err)
+	}
+
+	return nil
+}
+
+// restore moves the latest archived record of the id back as its next version, given that the
+// invoker is its owner or an admin and that its id has not been taken again in the meantime
+func (f family[T, P]) restore(ctx contractapi.TransactionContextInterface, id int) error {
+	r, k, err := f.getArchived(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = authorizeOwner(ctx, P(r).owner()); err != nil {
+		return fmt.Errorf(`restore %s %d failed - %w`, f.store.kind, id, err)
+	}
+
+	exists, err := f.store.exists(ctx, id)
+	if err != nil {
+		return fmt.Errorf(`restore %s failed - %w`, f.store.kind, err)
+	}
+
+	if exists {
+		return errorf(CodeAlreadyExists, `%s with id %d already exists`, f.store.kind, id)
+	}
+
+	P(r).setVersion(P(r).version() + 1)
+	if err = f.stamp(ctx, r, false); err != nil {
+		return err
+	}
+
+	if err = f.validate(r); err != nil {
+		return err
+	}
+
+	if err = f.endorse(ctx, r); err != nil {
+		return err
+	}
+
+	if err = f.store.put(ctx, r); err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().DelState(k); err != nil {
+		return fmt.Errorf(`deleting archive failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return f.emit(ctx, EventAssetRestored, nil, r)
+}
+
+// purge removes the latest archived record of the id for good, which only admins of its owner
+// may do
+func (f family[T, P]) purge(ctx contractapi.TransactionContextInterface, id int) error {
+	r, k, err := f.getArchived(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	admin, err := isAdmin(ctx, P(r).owner())
+	if err != nil {
+		return err
+	}
+
+	if !admin {
+		return errorf(CodeForbidden, `only an admin of the owner can purge %s %d`, f.store.kind, id)
+	}
+
+	if err = ctx.GetStub().DelState(k); err != nil {
+		return fmt.Errorf(`deleting archive failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return f.emit(ctx, EventAssetPurged, r, nil)
+}
+
+// archived returns a page of the archived records of the kind in key order
+func (f family[T, P]) archived(ctx contractapi.TransactionContextInterface, pageSize int, bookmark string) (*Page, error) {
+	if err := validPageSize(pageSize); err != nil {
+		return nil, err
+	}
+
+	itr, meta, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(archiveKey, []string{f.store.kind}, int32(pageSize), bookmark)
+	if err != nil {
+		return nil, fmt.Errorf(`get archived %s state by partial composite key with pagination failed - %w`, f.store.kind, err)
+	}
+	defer itr.Close()
+
+	as := []*ArchivedRecord{}
+	for itr.HasNext() {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf(`iterating next archived %s failed - %w`, f.store.kind, err)
+		}
+
+		a := ArchivedRecord{Record: new(T)}
+		if err = json.Unmarshal(res.Value, &a); err != nil {
+			return nil, fmt.Errorf(`unmarshal of archived %s failed - %w`, f.store.kind, err)
+		}
+
+		as = append(as, &a)
+	}
+
+	return &Page{
+		Bookmark:            meta.GetBookmark(),
+		FetchedRecordsCount: int(meta.GetFetchedRecordsCount()),
+		Records:             as,
+	}, nil
+}
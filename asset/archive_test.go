@@ -0,0 +1,226 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+// archivedAssetPage is a page of archived records decoded as assets
+type archivedAssetPage struct {
+	Bookmark            string `json:"bookmark"`
+	FetchedRecordsCount int    `json:"fetchedRecordsCount"`
+	Records             []struct {
+		DeletedAt string `json:"deletedAt"`
+		DeletedBy string `json:"deletedBy"`
+		DocType   string `json:"docType"`
+		ID        int    `json:"id"`
+		Kind      string `json:"kind"`
+		Record    Asset  `json:"record"`
+	} `json:"records"`
+}
+
+func getArchivedAssets(stub *queryStub, t *testing.T) archivedAssetPage {
+	res := stub.invoke(`1`, "GetArchivedAssets", kindAsset, "10", ``)
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var page archivedAssetPage
+	if err := json.Unmarshal(res.Payload, &page); err != nil {
+		t.Fatalf("failed to unmarshal page - %s", err.Error())
+	}
+
+	return page
+}
+
+func TestSmartContractDeleteArchivesAsset(t *testing.T) {
+	stub := newQueryStub()
+	admin := ownerOf(stub.MockStub, t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub.MockStub, t)
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = adminIdentity
+	stub.now = &timestamp.Timestamp{Seconds: 1700000000}
+	if res := stub.invoke(`2`, "DeleteAsset", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`3`, "GetAsset", "5"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+
+	page := getArchivedAssets(stub, t)
+	if page.FetchedRecordsCount != 1 {
+		t.Fatalf(`expected the deleted asset in the archive, got %d records`, page.FetchedRecordsCount)
+	}
+
+	a := page.Records[0]
+	if a.DeletedAt != `2023-11-14T22:13:20.000000000Z` || a.DeletedBy != admin || a.DocType != docArchived || a.ID != 5 ||
+		a.Kind != kindAsset || a.Record.Owner != alice || a.Record.Version != 1 {
+		t.Fatalf(`unexpected archived asset %s`, marshal(a, t))
+	}
+
+	// the owner may restore the asset, which then moves on to its next version
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`4`, "RestoreAsset", kindAsset, "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`5`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var restored Asset
+	if err := json.Unmarshal(res.Payload, &restored); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if restored.Owner != alice || restored.Version != 2 || restored.UpdatedBy != alice {
+		t.Fatalf(`unexpected restored asset %s`, res.Payload)
+	}
+
+	if page = getArchivedAssets(stub, t); page.FetchedRecordsCount != 0 {
+		t.Fatalf(`expected an empty archive after the restore, got %d records`, page.FetchedRecordsCount)
+	}
+
+	if res := stub.invoke(`6`, "RestoreAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+}
+
+func TestSmartContractRestoreAssetTakenAgain(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`2`, "DeleteAsset", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`3`, "CreateAsset", clrBrown, "5", "", "200"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`4`, "RestoreAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeAlreadyExists {
+		t.Fatalf(errExpect, CodeAlreadyExists, res.Message)
+	}
+}
+
+func TestSmartContractPurgeAsset(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// only archived records can be purged
+	if res := stub.invoke(`2`, "PurgeAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+
+	if res := stub.invoke(`3`, "DeleteAsset", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`4`, "PurgeAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	stub.Creator = adminIdentity
+	if res := stub.invoke(`5`, "PurgeAsset", kindAsset, "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if page := getArchivedAssets(stub, t); page.FetchedRecordsCount != 0 {
+		t.Fatalf(`expected an empty archive after the purge, got %d records`, page.FetchedRecordsCount)
+	}
+
+	if res := stub.invoke(`6`, "RestoreAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+}
+
+func TestSmartContractArchiveKeepsEveryDeletion(t *testing.T) {
+	stub := newQueryStub()
+	for i, clr := range []string{clrBlue, clrBrown} {
+		stub.now = &timestamp.Timestamp{Seconds: int64(1700000000 + i)}
+		if res := stub.invoke(`1`, "CreateAsset", clr, "5", "", "100"); res.Status != shim.OK {
+			t.Fatalf(errOK, res.Status, res.Message)
+		}
+
+		if res := stub.invoke(`2`, "DeleteAsset", "5"); res.Status != shim.OK {
+			t.Fatalf(errOK, res.Status, res.Message)
+		}
+	}
+
+	if page := getArchivedAssets(stub, t); page.FetchedRecordsCount != 2 {
+		t.Fatalf(`expected both deletions in the archive, got %d records`, page.FetchedRecordsCount)
+	}
+
+	// the latest deletion is restored, while the earlier one remains archived
+	if res := stub.invoke(`3`, "RestoreAsset", kindAsset, "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`4`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var restored Asset
+	if err := json.Unmarshal(res.Payload, &restored); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if restored.Color != clrBrown {
+		t.Fatalf(errExpect, clrBrown, restored.Color)
+	}
+
+	page := getArchivedAssets(stub, t)
+	if page.FetchedRecordsCount != 1 || page.Records[0].Record.Color != clrBlue {
+		t.Fatalf(`unexpected archive %s`, marshal(page, t))
+	}
+
+	if res = stub.invoke(`5`, "PurgeAsset", kindAsset, "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if page = getArchivedAssets(stub, t); page.FetchedRecordsCount != 0 {
+		t.Fatalf(`expected an empty archive after the purge, got %d records`, page.FetchedRecordsCount)
+	}
+}
+
+func TestSmartContractRestoreAssetOfKind(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "CreateVehicle", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`2`, "DeleteVehicle", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// the archives are kept per kind, hence there is no asset of the id to restore
+	if res := stub.invoke(`3`, "RestoreAsset", kindAsset, "5"); DecodeError(res.Message).Code != CodeNotFound {
+		t.Fatalf(errExpect, CodeNotFound, res.Message)
+	}
+
+	if res := stub.invoke(`4`, "RestoreAsset", "ship", "5"); DecodeError(res.Message).Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res.Message)
+	}
+
+	if res := stub.invoke(`5`, "RestoreAsset", kindVehicle, "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`6`, "GetVehicle", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
@@ -0,0 +1,83 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"time"
+)
+
+// timestampLayout formats the audit timestamps in UTC at a fixed width, so that they
+// sort chronologically as strings, which is how CouchDB compares them in range queries
+const timestampLayout = `2006-01-02T15:04:05.000000000Z`
+
+// auditFields are the JSON fields of the audit stamps of every record
+var auditFields = []string{`createdAt`, `createdBy`, `updatedAt`, `updatedBy`}
+
+// the audit timestamps range queries may filter by, along with their CouchDB indexes
+var timestampIndexes = map[string][]string{
+	`createdAt`: {`_design/indexCreatedAtDoc`, `indexCreatedAt`},
+	`updatedAt`: {`_design/indexUpdatedAtDoc`, `indexUpdatedAt`},
+}
+
+// txTimestamp returns the timestamp of the transaction, which all endorsers agree on
+func txTimestamp(ctx contractapi.TransactionContextInterface) (string, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return ``, fmt.Errorf(`get transaction timestamp failed - %w`, err)
+	}
+
+	return time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC().Format(timestampLayout), nil
+}
+
+// stamp records the transaction and its invoker as the last change of a record, and also
+// as its creation if created is set
+func (f family[T, P]) stamp(ctx contractapi.TransactionContextInterface, r P, created bool) error {
+	at, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	by, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		r.setCreated(at, by)
+	}
+	r.setUpdated(at, by)
+
+	return nil
+}
+
+// queryByTimestamp returns the records whose audit timestamp lies in [from, to), given in
+// RFC 3339 where an empty bound leaves the range open
+func (f family[T, P]) queryByTimestamp(ctx contractapi.TransactionContextInterface, field, from, to string, pageSize int, bookmark string) (*Page, error) {
+	index, ok := timestampIndexes[field]
+	if !ok {
+		return nil, errorf(CodeInvalid, `%s is not a timestamp of the records`, field)
+	}
+
+	bounds := map[string]interface{}{}
+	for op, bound := range map[string]string{`$gte`: from, `$lt`: to} {
+		if bound == `` {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339Nano, bound)
+		if err != nil {
+			return nil, errorf(CodeInvalid, `bound %s is not an RFC 3339 timestamp - %w`, bound, err)
+		}
+		bounds[op] = t.UTC().Format(timestampLayout)
+	}
+
+	// an open range still requires the field to exist for the index to apply
+	if len(bounds) == 0 {
+		bounds[`$gt`] = nil
+	}
+
+	return f.store.query(ctx, map[string]interface{}{
+		`docType`: f.store.kind,
+		field:     bounds,
+	}, index, pageSize, bookmark)
+}
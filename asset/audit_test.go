@@ -0,0 +1,85 @@
+package asset
+
+import (
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+func TestSmartContractAuditStamps(t *testing.T) {
+	stub := newQueryStub()
+	stub.now = &timestamp.Timestamp{Seconds: 1700000000, Nanos: 5}
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	admin := ownerOf(stub.MockStub, t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub.MockStub, t)
+
+	stub.Creator = adminIdentity
+	stub.now = &timestamp.Timestamp{Seconds: 1700000060}
+	if res := stub.invoke(`2`, "TransferAsset", "5", alice); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	stub.now = &timestamp.Timestamp{Seconds: 1700000120}
+	if res := stub.invoke(`3`, "ChangeAssetColour", "5", clrBrown); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	page := queryAssets(stub, t, "QueryAssetsByTimestamp", kindAsset, "createdAt", "", "", "10", ``)
+	if page.FetchedRecordsCount != 1 {
+		t.Fatalf(`expected the stamped asset, got %d records`, page.FetchedRecordsCount)
+	}
+
+	a := page.Records[0]
+	in := Asset{CreatedAt: `2023-11-14T22:13:20.000000005Z`, CreatedBy: admin, UpdatedAt: `2023-11-14T22:15:20.000000000Z`, UpdatedBy: alice}
+	if a.CreatedAt != in.CreatedAt || a.CreatedBy != in.CreatedBy || a.UpdatedAt != in.UpdatedAt || a.UpdatedBy != in.UpdatedBy {
+		t.Fatalf(errExpect, marshal(in, t), marshal(a, t))
+	}
+}
+
+func TestSmartContractQueryAssetsByTimestamp(t *testing.T) {
+	stub := newQueryStub()
+	for i, sec := range []int64{100, 200, 300} {
+		stub.now = &timestamp.Timestamp{Seconds: sec}
+		if res := stub.invoke(`1`, "CreateAsset", clrBlue, string(rune('1'+i)), "", "100"); res.Status != shim.OK {
+			t.Fatalf(errOK, res.Status, res.Message)
+		}
+	}
+
+	stub.now = &timestamp.Timestamp{Seconds: 400}
+	if res := stub.invoke(`2`, "ChangeAssetValue", "1", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	for _, c := range []struct {
+		field, from, to string
+		ids             []int
+	}{
+		{`createdAt`, `1970-01-01T00:02:00Z`, `1970-01-01T00:05:00Z`, []int{2}},
+		{`createdAt`, `1970-01-01T01:00:00+01:00`, ``, []int{1, 2, 3}},
+		{`updatedAt`, `1970-01-01T00:04:00Z`, ``, []int{1, 3}},
+	} {
+		page := queryAssets(stub, t, "QueryAssetsByTimestamp", kindAsset, c.field, c.from, c.to, "10", ``)
+		ids := []int{}
+		for _, a := range page.Records {
+			ids = append(ids, a.ID)
+		}
+
+		if string(marshal(ids, t)) != string(marshal(c.ids, t)) {
+			t.Fatalf(errExpect, marshal(c.ids, t), marshal(ids, t))
+		}
+	}
+
+	for _, args := range [][]string{
+		{"QueryAssetsByTimestamp", kindAsset, "owner", "", "", "10", ``},
+		{"QueryAssetsByTimestamp", kindAsset, "createdAt", "yesterday", "", "10", ``},
+	} {
+		if res := stub.invoke(`3`, args...); DecodeError(res.Message).Code != CodeInvalid {
+			t.Fatalf(errExpect, CodeInvalid, res.Message)
+		}
+	}
+}
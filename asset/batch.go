@@ -0,0 +1,131 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// defaultMaxBatchSize bounds the items of a batch, and hence its write set, unless configured otherwise
+const defaultMaxBatchSize = 100
+
+// BatchItem is an item of a batch, of which each batch transaction reads the fields it needs
+type BatchItem struct {
+	Color    string `json:"color"`
+	ID       int    `json:"id"`
+	NewOwner string `json:"newOwner"`
+	Owner    string `json:"owner"`
+	Value    string `json:"value"`
+	// Version is the version the record is expected at, where null skips the check
+	Version *int `json:"version"`
+}
+
+// BatchResult is the outcome of the item at Index of a batch, where Error is empty on success
+type BatchResult struct {
+	Error string `json:"error"`
+	ID    int    `json:"id"`
+	Index int    `json:"index"`
+	OK    bool   `json:"ok"`
+}
+
+// BatchReport lists the outcome of every item of a batch in their order
+type BatchReport struct {
+	Applied int           `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+func (i BatchItem) version() int {
+	if i.Version == nil {
+		return anyVersion
+	}
+
+	return *i.Version
+}
+
+func (s *SmartContract) maxBatchSize() int {
+	if s.MaxBatchSize > 0 {
+		return s.MaxBatchSize
+	}
+
+	return defaultMaxBatchSize
+}
+
+// decodeBatch parses a JSON array of at least one and at most limit items
+func decodeBatch(itemsJSON string, limit int) ([]BatchItem, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(itemsJSON)))
+	dec.DisallowUnknownFields()
+
+	var items []BatchItem
+	if err := dec.Decode(&items); err != nil {
+		return nil, errorf(CodeInvalid, `batch is not a JSON array of items - %w`, err)
+	}
+
+	if len(items) == 0 || len(items) > limit {
+		return nil, errorf(CodeInvalid, `batch of %d items should have between 1 and %d items`, len(items), limit)
+	}
+
+	return items, nil
+}
+
+// batch applies fn to every item of the batch in order. A batch either applies as a whole
+// or fails with the report of every item, in which case Fabric discards all of its writes.
+// The writes of a transaction are not visible to its own reads, hence an id may only appear
+// once in a batch.
+func (f family[T, P]) batch(itemsJSON string, limit int, fn func(item BatchItem) error) (*BatchReport, error) {
+	items, err := decodeBatch(itemsJSON, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BatchReport{Results: make([]BatchResult, len(items))}
+	seen := make(map[int]int, len(items))
+	var first error
+	failed := 0
+	for i, item := range items {
+		if j, ok := seen[item.ID]; ok {
+			err = errorf(CodeInvalid, `%s %d is also item %d of the batch`, f.store.kind, item.ID, j)
+		} else {
+			seen[item.ID] = i
+			err = fn(item)
+		}
+
+		report.Results[i] = BatchResult{ID: item.ID, Index: i, OK: err == nil}
+		if err != nil {
+			report.Results[i].Error = err.Error()
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		byts, err := json.Marshal(report)
+		if err != nil {
+			return nil, errorf(CodeInternal, `marshal %s batch report failed - %w`, f.store.kind, err)
+		}
+
+		return nil, errorf(CodeOf(first), `%d of %d items of the %s batch failed, hence none was applied: %s`, failed, len(items), f.store.kind, byts)
+	}
+	report.Applied = len(items)
+
+	return report, nil
+}
+
+func (f family[T, P]) createBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error) {
+	return f.batch(itemsJSON, limit, func(item BatchItem) error {
+		return f.create(ctx, item.Color, item.ID, item.Owner, item.Value)
+	})
+}
+
+func (f family[T, P]) updateBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error) {
+	return f.batch(itemsJSON, limit, func(item BatchItem) error {
+		return f.update(ctx, item.Color, item.ID, item.Owner, item.Value, item.version())
+	})
+}
+
+func (f family[T, P]) transferBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error) {
+	return f.batch(itemsJSON, limit, func(item BatchItem) error {
+		return f.transfer(ctx, item.ID, item.NewOwner, item.version())
+	})
+}
@@ -0,0 +1,95 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"strings"
+	"testing"
+)
+
+func invokeBatch(stub *queryStub, t *testing.T, fn string, kind string, items []BatchItem) BatchReport {
+	res := stub.invoke(`1`, fn, kind, string(marshal(items, t)))
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var report BatchReport
+	if err := json.Unmarshal(res.Payload, &report); err != nil {
+		t.Fatalf("failed to unmarshal batch report - %s", err.Error())
+	}
+
+	return report
+}
+
+func TestSmartContractAssetsBatch(t *testing.T) {
+	stub := newQueryStub()
+	report := invokeBatch(stub, t, "CreateAssetsBatch", kindAsset, []BatchItem{
+		{Color: clrBlue, ID: 1, Owner: ownrDavid, Value: "100"},
+		{Color: clrBrown, ID: 2, Owner: ownrDavid, Value: "200 USD"},
+		{Color: clrBlue, ID: 3, Owner: "Arnold", Value: "300"},
+	})
+	if report.Applied != 3 || len(report.Results) != 3 || !report.Results[2].OK || report.Results[2].ID != 3 {
+		t.Fatalf(`unexpected batch report %s`, marshal(report, t))
+	}
+
+	one, two := 1, 2
+	invokeBatch(stub, t, "UpdateAssetsBatch", kindAsset, []BatchItem{
+		{Color: clrBrown, ID: 1, Owner: ownrDavid, Value: "150", Version: &one},
+		{Color: clrBrown, ID: 3, Owner: "Arnold", Value: "350"},
+	})
+
+	invokeBatch(stub, t, "TransferAssetsBatch", kindAsset, []BatchItem{
+		{ID: 1, NewOwner: "Arnold", Version: &two},
+		{ID: 2, NewOwner: "Arnold"},
+	})
+}
+
+func TestSmartContractAssetsBatchFailsAsAWhole(t *testing.T) {
+	stub := newQueryStub()
+	res := stub.invoke(`1`, "CreateAssetsBatch", kindAsset, string(marshal([]BatchItem{
+		{Color: clrBlue, ID: 1, Owner: ownrDavid, Value: "100"},
+		{Color: "plaid", ID: 2, Owner: ownrDavid, Value: "200"},
+		{Color: clrBlue, ID: 1, Owner: ownrDavid, Value: "300"},
+	}, t)))
+	if DecodeError(res.Message).Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res.Message)
+	}
+
+	var report BatchReport
+	if err := json.Unmarshal([]byte(res.Message[strings.Index(res.Message, `{`):]), &report); err != nil {
+		t.Fatalf("failed to unmarshal batch report - %s", err.Error())
+	}
+
+	if report.Applied != 0 || !report.Results[0].OK || report.Results[1].OK || report.Results[2].OK {
+		t.Fatalf(`unexpected batch report %s`, marshal(report, t))
+	}
+
+	if DecodeError(report.Results[1].Error).Code != CodeInvalid || !strings.Contains(report.Results[2].Error, `also item 0`) {
+		t.Fatalf(`unexpected batch report %s`, marshal(report, t))
+	}
+}
+
+func TestSmartContractAssetsBatchConflict(t *testing.T) {
+	stub := newQueryStub()
+	invokeBatch(stub, t, "CreateAssetsBatch", kindAsset, []BatchItem{{Color: clrBlue, ID: 1, Owner: ownrDavid, Value: "100"}})
+
+	stale := 0
+	res := stub.invoke(`2`, "TransferAssetsBatch", kindAsset, string(marshal([]BatchItem{{ID: 1, NewOwner: "Arnold", Version: &stale}}, t)))
+	if DecodeError(res.Message).Code != CodeConflict {
+		t.Fatalf(errExpect, CodeConflict, res.Message)
+	}
+}
+
+func TestSmartContractAssetsBatchSize(t *testing.T) {
+	stub := newQueryStub()
+	items := make([]BatchItem, defaultMaxBatchSize+1)
+	for i := range items {
+		items[i] = BatchItem{Color: clrBlue, ID: i, Owner: ownrDavid, Value: "100"}
+	}
+
+	for _, arg := range []string{`[]`, `{}`, `[{"colour": "blue"}]`, string(marshal(items, t))} {
+		if res := stub.invoke(`1`, "CreateAssetsBatch", kindAsset, arg); DecodeError(res.Message).Code != CodeInvalid {
+			t.Fatalf(errExpect, CodeInvalid, res.Message)
+		}
+	}
+}
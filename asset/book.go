@@ -0,0 +1,152 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+var bookFamily = newFamily[Book](kindBook)
+
+// Book attributes are defined in alphabetical order to make JSON struct deterministic
+type Book struct {
+	Author    string `json:"author"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"createdAt"`
+	CreatedBy string `json:"createdBy"`
+	DocType   string `json:"docType"`
+	Edition   int    `json:"edition"`
+	ID        int    `json:"id"`
+	ISBN      string `json:"isbn"`
+	Owner     string `json:"owner"`
+	Title     string `json:"title"`
+	UpdatedAt string `json:"updatedAt"`
+	UpdatedBy string `json:"updatedBy"`
+	Value     Money  `json:"value"`
+	Version   int    `json:"version"`
+}
+
+func (s *SmartContract) CreateBook(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return bookFamily.create(ctx, color, id, owner, val)
+}
+
+func (s *SmartContract) GetBook(ctx contractapi.TransactionContextInterface, id int) (*Book, error) {
+	return bookFamily.get(ctx, id)
+}
+
+func (s *SmartContract) UpdateBook(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return bookFamily.update(ctx, color, id, owner, val, anyVersion)
+}
+
+// CreateBookWithDetails creates a book along with the attributes specific to books
+func (s *SmartContract) CreateBookWithDetails(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string, isbn string, title string, author string, edition int) error {
+	args := &validator{}
+	b := &Book{Author: author, Color: color, Edition: edition, ID: id, ISBN: isbn, Owner: owner, Title: title, Value: args.value(val)}
+
+	return bookFamily.createRecord(ctx, b, args.violations...)
+}
+
+// UpdateBookDetails replaces the attributes specific to books of an existing book
+func (s *SmartContract) UpdateBookDetails(ctx contractapi.TransactionContextInterface, id int, isbn string, title string, author string, edition int) error {
+	return bookFamily.modify(ctx, id, EventAssetUpdated, func(b *Book) error {
+		b.ISBN = isbn
+		b.Title = title
+		b.Author = author
+		b.Edition = edition
+
+		return nil
+	})
+}
+
+func (s *SmartContract) DeleteBook(ctx contractapi.TransactionContextInterface, id int) error {
+	return bookFamily.delete(ctx, id)
+}
+
+func (s *SmartContract) TransferBook(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
+	return bookFamily.transfer(ctx, id, newOwner, anyVersion)
+}
+
+func (s *SmartContract) GetAllBooks(ctx contractapi.TransactionContextInterface) ([]*Book, error) {
+	return bookFamily.getAll(ctx, s.maxQueryResults())
+}
+
+// GetBooksByOwner returns the books of an owner from the owner index, which also works on LevelDB
+func (s *SmartContract) GetBooksByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Book, error) {
+	return bookFamily.byOwner(ctx, owner, s.maxQueryResults())
+}
+
+func (s *SmartContract) GetBooksByColor(ctx contractapi.TransactionContextInterface, clr string) ([]*Book, error) {
+	return bookFamily.byColor(ctx, clr, s.maxQueryResults())
+}
+
+func (s *SmartContract) BookExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	return bookFamily.exists(ctx, id)
+}
+
+func (s *SmartContract) ChangeBookColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
+	return bookFamily.changeColour(ctx, id, clr)
+}
+
+func (s *SmartContract) ChangeBookValue(ctx contractapi.TransactionContextInterface, id int, val string) error {
+	return bookFamily.changeValue(ctx, id, val)
+}
+
+// check applies the rules of books once any of their details is given, as books created
+// without details only carry the common attributes
+func (b *Book) check(v *validator) {
+	if b.ISBN == `` && b.Title == `` && b.Author == `` && b.Edition == 0 {
+		return
+	}
+
+	v.check(validISBN(b.ISBN), `isbn`, `isbn %s is not a valid ISBN-10 or ISBN-13`, b.ISBN)
+	v.text(`title`, b.Title, maxTextLength)
+	v.text(`author`, b.Author, maxTextLength)
+	v.check(b.Edition >= 1, `edition`, `edition %d should be a positive number`, b.Edition)
+}
+
+// validISBN verifies the check digit of an ISBN-10 or ISBN-13 ignoring hyphens and spaces
+func validISBN(isbn string) bool {
+	digits := strings.NewReplacer(`-`, ``, ` `, ``).Replace(isbn)
+	switch len(digits) {
+	case 10:
+		sum := 0
+		for i, c := range digits {
+			d := int(c - '0')
+			if c == 'X' && i == 9 {
+				d = 10
+			} else if c < '0' || c > '9' {
+				return false
+			}
+			sum += (10 - i) * d
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i, c := range digits {
+			if c < '0' || c > '9' {
+				return false
+			}
+			d := int(c - '0')
+			if i%2 == 1 {
+				d *= 3
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	default:
+		return false
+	}
+}
+
+func (b *Book) id() int                  { return b.ID }
+func (b *Book) setID(id int)             { b.ID = id }
+func (b *Book) owner() string            { return b.Owner }
+func (b *Book) setOwner(owner string)    { b.Owner = owner }
+func (b *Book) color() string            { return b.Color }
+func (b *Book) setColor(clr string)      { b.Color = clr }
+func (b *Book) value() Money             { return b.Value }
+func (b *Book) setValue(val Money)       { b.Value = val }
+func (b *Book) setCreated(at, by string) { b.CreatedAt, b.CreatedBy = at, by }
+func (b *Book) setUpdated(at, by string) { b.UpdatedAt, b.UpdatedBy = at, by }
+func (b *Book) version() int             { return b.Version }
+func (b *Book) setVersion(ver int)       { b.Version = ver }
+func (b *Book) setDocType(kind string)   { b.DocType = kind }
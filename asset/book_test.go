@@ -1,7 +1,6 @@
 package asset
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
@@ -12,7 +11,7 @@ import (
 )
 
 var (
-	testBook = Asset{Color: "brown", ID: 88, Owner: "Arnold", Value: 989}
+	testBook = Book{Color: "brown", DocType: kindBook, ID: 88, Owner: "Arnold", Value: euros(989)}
 )
 
 func marshalBook() []byte {
@@ -25,7 +24,7 @@ func marshalBook() []byte {
 }
 
 func getBookState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
-	out, err := stub.GetState(strconv.Itoa(id))
+	out, err := stub.GetState(compositeKey(kindBook, id, t))
 	if err != nil {
 		t.Fatalf("failed to retrieve asset info - %s", err.Error())
 	}
@@ -35,14 +34,16 @@ func getBookState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
 
 func testCreateBook(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`4`, [][]byte{
-		[]byte("CreateBook"), []byte(testBook.Color), []byte(strconv.Itoa(testBook.ID)), []byte(testBook.Owner), []byte(strconv.Itoa(testBook.Value)),
+		[]byte("CreateBook"), []byte(testBook.Color), []byte(strconv.Itoa(testBook.ID)), []byte(testBook.Owner), []byte(testBook.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
+
+	testBook.Version = 1
 }
 
 func marshalBooks() []byte {
-	byts, err := json.Marshal(assets)
+	byts, err := json.Marshal([]Book{testBook})
 	if err != nil {
 		log.Fatal(fmt.Sprintf("failed to marshal assets - %s", err.Error()))
 	}
@@ -52,7 +53,7 @@ func marshalBooks() []byte {
 
 func testUpdateBook(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`5`, [][]byte{
-		[]byte("UpdateBook"), []byte(testBook.Color), []byte(strconv.Itoa(testBook.ID)), []byte(testBook.Owner), []byte(strconv.Itoa(testBook.Value)),
+		[]byte("UpdateBook"), []byte(testBook.Color), []byte(strconv.Itoa(testBook.ID)), []byte(testBook.Owner), []byte(testBook.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
@@ -68,7 +69,7 @@ func TestSmartContractGetBook(t *testing.T) {
 	}
 
 	in := marshalBook()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -78,13 +79,14 @@ func TestSmartContractUpdateBook(t *testing.T) {
 	testCreateBook(stub, t)
 
 	testBook.Color = clrBlue
-	testBook.Value = 1500
+	testBook.Value = euros(1500)
 	testUpdateBook(stub, t)
+	testBook.Version = 2
 
 	in := marshalBook()
 	out := getBookState(stub, testBook.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -99,10 +101,11 @@ func TestSmartContractChangeBookValue(t *testing.T) {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
-	testBook.Value = 888
+	testBook.Value = euros(888)
+	testBook.Version = 2
 	out := getBookState(stub, testBook.ID, t)
 	in := marshalBook()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -118,9 +121,10 @@ func TestSmartContractChangeBookColour(t *testing.T) {
 	}
 
 	testBook.Color = clrBrown
+	testBook.Version = 2
 	out := getBookState(stub, testBook.ID, t)
 	in := marshalBook()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -128,14 +132,16 @@ func TestSmartContractChangeBookColour(t *testing.T) {
 func TestSmartContractGetAllBooks(t *testing.T) {
 	stub := newMockStub()
 	testInitLedger(stub, t)
+	testCreateBook(stub, t)
 
+	// assets seeded by the ledger initialisation must not leak into the book namespace
 	res := stub.MockInvoke(`2`, [][]byte{[]byte("GetAllBooks")})
 	if res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
 	in := marshalBooks()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -163,7 +169,7 @@ func TestSmartContractCreateBook(t *testing.T) {
 	in := marshalBook()
 	out := getBookState(stub, testBook.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -179,9 +185,44 @@ func TestSmartContractTransferBook(t *testing.T) {
 	}
 
 	testBook.Owner = ownrDavid
+	testBook.Version = 2
 	out := getBookState(stub, testBook.ID, t)
 	in := marshalBook()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
+
+func TestSmartContractCreateBookWithDetails(t *testing.T) {
+	stub := newMockStub()
+	b := Book{Author: "Frank Herbert", Color: clrBlue, DocType: kindBook, Edition: 2, ID: 101, ISBN: "978-0-306-40615-7", Owner: ownrDavid, Title: "Dune", Value: euros(25), Version: 1}
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateBookWithDetails"), []byte(b.Color), []byte(strconv.Itoa(b.ID)), []byte(b.Owner), []byte(b.Value.String()),
+		[]byte(b.ISBN), []byte(b.Title), []byte(b.Author), []byte(strconv.Itoa(b.Edition)),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	in, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("failed to marshal book - %s", err.Error())
+	}
+
+	out := getBookState(stub, b.ID, t)
+	if !sameRecords(in, out, t) {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractUpdateBookWithInvalidISBN(t *testing.T) {
+	stub := newMockStub()
+	testCreateBook(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("UpdateBookDetails"), []byte(strconv.Itoa(testBook.ID)),
+		[]byte("978-0-306-40615-8"), []byte("Dune"), []byte("Frank Herbert"), []byte("1"),
+	}); res.Status == shim.OK {
+		t.Fatalf(`book with an invalid isbn check digit should be rejected`)
+	}
+}
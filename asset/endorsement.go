@@ -0,0 +1,132 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"sort"
+)
+
+// endorse sets the key level endorsement policy of a record, so that changing it
+// requires the endorsement of a peer of the org of its owner
+func (f family[T, P]) endorse(ctx contractapi.TransactionContextInterface, r P) error {
+	org, err := owningOrg(ctx, r.owner())
+	if err != nil {
+		return err
+	}
+
+	policy, err := orgPolicy(org)
+	if err != nil {
+		return err
+	}
+
+	k, err := key(ctx, f.store.kind, r.id())
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().SetStateValidationParameter(k, policy); err != nil {
+		return fmt.Errorf(`set endorsement policy failed for %s %d - %w`, f.store.kind, r.id(), err)
+	}
+
+	return nil
+}
+
+// orgPolicy returns a key level endorsement policy requiring a peer of the org
+func orgPolicy(org string) ([]byte, error) {
+	ep, err := statebased.NewStateEP(nil)
+	if err != nil {
+		return nil, fmt.Errorf(`create endorsement policy failed - %w`, err)
+	}
+
+	if err = ep.AddOrgs(statebased.RoleTypePeer, org); err != nil {
+		return nil, fmt.Errorf(`add %s to endorsement policy failed - %w`, org, err)
+	}
+
+	policy, err := ep.Policy()
+	if err != nil {
+		return nil, fmt.Errorf(`marshal endorsement policy failed - %w`, err)
+	}
+
+	return policy, nil
+}
+
+// endorsingOrgs returns the orgs which must endorse changes of a record, where none
+// means that the endorsement policy of the chaincode applies
+func (f family[T, P]) endorsingOrgs(ctx contractapi.TransactionContextInterface, id int) ([]string, error) {
+	if _, err := f.store.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	k, err := key(ctx, f.store.kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := ctx.GetStub().GetStateValidationParameter(k)
+	if err != nil {
+		return nil, fmt.Errorf(`get endorsement policy failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	ep, err := statebased.NewStateEP(policy)
+	if err != nil {
+		return nil, fmt.Errorf(`unmarshal endorsement policy failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	orgs := ep.ListOrgs()
+	sort.Strings(orgs)
+
+	return orgs, nil
+}
+
+// resetEndorsement ties the endorsement policy of a record to the org of its current owner
+func (f family[T, P]) resetEndorsement(ctx contractapi.TransactionContextInterface, id int) error {
+	r, err := f.store.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return f.endorse(ctx, r)
+}
+
+// owningOrg returns the org of an owner, or the org of the invoker for owners which
+// are not recorded as client identities
+func owningOrg(ctx contractapi.TransactionContextInterface, owner string) (string, error) {
+	if org := ownerMSP(owner); org != `` {
+		return org, nil
+	}
+
+	ci := ctx.GetClientIdentity()
+	if ci == nil {
+		return ``, fmt.Errorf(`client identity of the transaction is not available`)
+	}
+
+	org, err := ci.GetMSPID()
+	if err != nil {
+		return ``, fmt.Errorf(`get msp id of the client failed - %w`, err)
+	}
+
+	return org, nil
+}
+
+// GetAssetEndorsementPolicy returns the orgs which must endorse changes of a record of
+// the given kind
+func (s *SmartContract) GetAssetEndorsementPolicy(ctx contractapi.TransactionContextInterface, kind string, id int) ([]string, error) {
+	f, err := familyOf(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.endorsingOrgs(ctx, id)
+}
+
+// ResetAssetEndorsementPolicy ties the endorsement policy of a record of the given kind
+// back to the org of its owner
+func (s *SmartContract) ResetAssetEndorsementPolicy(ctx contractapi.TransactionContextInterface, kind string, id int) error {
+	f, err := familyOf(kind)
+	if err != nil {
+		return err
+	}
+
+	return f.resetEndorsement(ctx, id)
+}
@@ -0,0 +1,95 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"testing"
+)
+
+func getEndorsingOrgs(stub *shimtest.MockStub, id string, t *testing.T) string {
+	res := stub.MockInvoke(`e`+id, toArgs([]string{"GetAssetEndorsementPolicy", kindAsset, id}))
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	return string(res.Payload)
+}
+
+func TestSmartContractAssetEndorsementPolicy(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+	if res := stub.MockInvoke(`1`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if in, out := `["Org1MSP"]`, getEndorsingOrgs(stub, "5", t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	stub.Creator = carolIdentity
+	carol := ownerOf(stub, t)
+	stub.Creator = aliceIdentity
+	if res := stub.MockInvoke(`2`, toArgs([]string{"TransferAsset", "5", carol})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if in, out := `["Org2MSP"]`, getEndorsingOrgs(stub, "5", t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	// updates handing the record over move its endorsement too
+	stub.Creator = carolIdentity
+	if res := stub.MockInvoke(`3`, toArgs([]string{"UpdateAsset", clrBrown, "5", ownerOf(stub, t), "200"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.MockInvoke(`4`, toArgs([]string{"CreateAsset", clrBlue, "6", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub, t)
+	stub.Creator = carolIdentity
+	if res := stub.MockInvoke(`5`, toArgs([]string{"UpdateAsset", clrBrown, "6", alice, "200"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if in, out := `["Org1MSP"]`, getEndorsingOrgs(stub, "6", t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractResetAssetEndorsementPolicy(t *testing.T) {
+	stub := newMockStub()
+
+	// records written before key-level policies are governed by the endorsement policy of the chaincode
+	k, err := stub.CreateCompositeKey(kindAsset, []string{"1"})
+	if err != nil {
+		t.Fatalf("failed to create key - %s", err.Error())
+	}
+
+	stub.MockTransactionStart(`0`)
+	if err = stub.PutState(k, marshal(Asset{Color: clrBlue, DocType: kindAsset, ID: 1, Owner: ownrDavid, Value: euros(5), Version: 1}, t)); err != nil {
+		t.Fatalf("failed to put state - %s", err.Error())
+	}
+	stub.MockTransactionEnd(`0`)
+
+	if in, out := `[]`, getEndorsingOrgs(stub, "1", t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	stub.Creator = aliceIdentity
+	res := stub.MockInvoke(`1`, toArgs([]string{"ResetAssetEndorsementPolicy", kindAsset, "1"}))
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	stub.Creator = adminIdentity
+	if res = stub.MockInvoke(`2`, toArgs([]string{"ResetAssetEndorsementPolicy", kindAsset, "1"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if in, out := `["Org1MSP"]`, getEndorsingOrgs(stub, "1", t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+}
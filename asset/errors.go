@@ -0,0 +1,112 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Code classifies the errors of the transactions. Its numeric values follow the gRPC
+// status codes and never change, so that clients may rely on them.
+type Code int
+
+const (
+	CodeInvalid       Code = 3
+	CodeNotFound      Code = 5
+	CodeAlreadyExists Code = 6
+	CodeForbidden     Code = 7
+	CodeConflict      Code = 10
+	CodeInternal      Code = 13
+)
+
+var codeNames = map[Code]string{
+	CodeInvalid:       `INVALID_ARGUMENT`,
+	CodeNotFound:      `NOT_FOUND`,
+	CodeAlreadyExists: `ALREADY_EXISTS`,
+	CodeForbidden:     `FORBIDDEN`,
+	CodeConflict:      `CONFLICT`,
+	CodeInternal:      `INTERNAL`,
+}
+
+// the sentinels of each code, matched by errors.Is against any error of the same code
+var (
+	ErrInvalid       = &Error{Code: CodeInvalid}
+	ErrNotFound      = &Error{Code: CodeNotFound}
+	ErrAlreadyExists = &Error{Code: CodeAlreadyExists}
+	ErrForbidden     = &Error{Code: CodeForbidden}
+	ErrConflict      = &Error{Code: CodeConflict}
+	ErrInternal      = &Error{Code: CodeInternal}
+)
+
+// codePattern finds the code of an error in a message, which clients usually receive
+// embedded in the message of the gateway or the SDK
+var codePattern = regexp.MustCompile(`([A-Z_]+)\(([0-9]+)\): `)
+
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+
+	return `UNKNOWN`
+}
+
+// Error is an error carrying a code. Its message starts with the name and the numeric
+// code, as in NOT_FOUND(5): asset with id 1 does not exist, which is how the code reaches
+// clients through the shim error response.
+type Error struct {
+	Code    Code
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(`%s(%d): %s`, e.Code, int(e.Code), e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches the sentinel of the code of the error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == `` && t.Code == e.Code
+}
+
+// errorf formats an error of the given code, retaining the error wrapped with %w if any
+func errorf(code Code, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), cause: errors.Unwrap(err)}
+}
+
+// CodeOf returns the code of an error, where errors without one are internal
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	var v *ValidationError
+	if errors.As(err, &v) {
+		return CodeInvalid
+	}
+
+	return CodeInternal
+}
+
+// DecodeError recovers the coded error from the message of a failed transaction as
+// received by a client, where messages without a code are internal errors
+func DecodeError(message string) *Error {
+	loc := codePattern.FindStringSubmatchIndex(message)
+	if loc == nil {
+		return &Error{Code: CodeInternal, Message: message}
+	}
+
+	code, err := strconv.Atoi(message[loc[4]:loc[5]])
+	if err != nil || Code(code).String() != message[loc[2]:loc[3]] {
+		return &Error{Code: CodeInternal, Message: message}
+	}
+
+	return &Error{Code: Code(code), Message: message[loc[1]:]}
+}
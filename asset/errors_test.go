@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	err := fmt.Errorf(`get asset failed - %w`, errorf(CodeNotFound, `asset with id %d does not exist`, 1))
+	if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Fatalf(`wrapped error should only match %s (%s)`, CodeNotFound, err)
+	}
+
+	if CodeOf(err) != CodeNotFound || CodeOf(errors.New(`boom`)) != CodeInternal {
+		t.Fatalf(`errors without a code should be internal`)
+	}
+
+	if v := (&ValidationError{Kind: kindAsset}); !errors.Is(v, ErrInvalid) || CodeOf(v) != CodeInvalid {
+		t.Fatalf(`validation errors should be invalid`)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	for in, out := range map[string]Error{
+		`NOT_FOUND(5): asset with id 1 does not exist`:                               {Code: CodeNotFound, Message: `asset with id 1 does not exist`},
+		`chaincode response 500, get asset failed - CONFLICT(10): asset 1 is locked`: {Code: CodeConflict, Message: `asset 1 is locked`},
+		`NOT_FOUND(6): mismatching name and code`:                                    {Code: CodeInternal, Message: `NOT_FOUND(6): mismatching name and code`},
+		`failed to connect`: {Code: CodeInternal, Message: `failed to connect`},
+	} {
+		if e := DecodeError(in); e.Code != out.Code || e.Message != out.Message {
+			t.Fatalf(errExpect, out.Error(), e.Error())
+		}
+	}
+}
+
+func TestSmartContractErrorCodes(t *testing.T) {
+	stub := newMockStub()
+	testCreate(stub, t)
+
+	for code, args := range map[Code][]string{
+		CodeNotFound:      {"GetAsset", "404"},
+		CodeAlreadyExists: {"CreateAsset", clrBlue, "88", "", "1"},
+		CodeInvalid:       {"IssueShares", kindAsset, "88", "0"},
+	} {
+		res := stub.MockInvoke(`2`, toArgs(args))
+		if res.Status == shim.OK {
+			t.Fatalf(`%s should fail`, args[0])
+		}
+
+		if e := DecodeError(res.Message); e.Code != code {
+			t.Fatalf(errExpect, code, res.Message)
+		}
+	}
+}
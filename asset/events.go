@@ -0,0 +1,164 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"sort"
+)
+
+// EventVersion is the version of the event payload schema, which is increased on
+// changes consumers can not ignore
+const EventVersion = 1
+
+// names of the chaincode events, which are also the operation of their payloads. Transfers
+// of the NFT contract emit its EventTransfer instead, whose payload carries the asset event.
+const (
+	EventAssetCreated       = `AssetCreated`
+	EventAssetUpdated       = `AssetUpdated`
+	EventAssetDeleted       = `AssetDeleted`
+	EventAssetTransferred   = `AssetTransferred`
+	EventAssetColourChanged = `AssetColourChanged`
+	EventAssetValueChanged  = `AssetValueChanged`
+	EventAssetRestored      = `AssetRestored`
+	EventAssetPurged        = `AssetPurged`
+	// EventSharesIssued and EventSharesTransferred report the units of the shares of a record
+	// moving from their old to their new holder, where an issue has no old holder
+	EventSharesIssued      = `SharesIssued`
+	EventSharesTransferred = `SharesTransferred`
+	// EventAssetBatch is emitted instead when a transaction changes several records, as
+	// Fabric only keeps the last event set by a transaction
+	EventAssetBatch = `AssetBatch`
+)
+
+// Event is the payload of the events reporting the change of a single record
+type Event struct {
+	Changed   []string `json:"changed"`
+	ID        int      `json:"id"`
+	Kind      string   `json:"kind"`
+	NewOwner  string   `json:"newOwner,omitempty"`
+	OldOwner  string   `json:"oldOwner,omitempty"`
+	Operation string   `json:"operation"`
+	Units     int      `json:"units,omitempty"`
+	Version   int      `json:"version"`
+}
+
+// BatchEvent is the payload of EventAssetBatch listing the changes in their order
+type BatchEvent struct {
+	Events  []Event `json:"events"`
+	Version int     `json:"version"`
+}
+
+// txContext buffers the events of a transaction until it succeeds, and carries the admin
+// orgs of the contract, which the before transaction hook sets
+type txContext struct {
+	contractapi.TransactionContext
+	adminMSPs []string
+	events    []Event
+}
+
+// GetTransactionContextHandler provides the transaction context buffering events
+func (s *SmartContract) GetTransactionContextHandler() contractapi.SettableTransactionContextInterface {
+	return new(txContext)
+}
+
+// GetAfterTransaction emits the events buffered by a successful transaction
+func (s *SmartContract) GetAfterTransaction() interface{} {
+	return s.emitEvents
+}
+
+func (s *SmartContract) emitEvents(ctx contractapi.TransactionContextInterface) error {
+	tc, ok := ctx.(*txContext)
+	if !ok || len(tc.events) == 0 {
+		return nil
+	}
+
+	if len(tc.events) == 1 {
+		return setEvent(ctx, tc.events[0].Operation, tc.events[0])
+	}
+
+	return setEvent(ctx, EventAssetBatch, BatchEvent{Events: tc.events, Version: EventVersion})
+}
+
+// emit reports the change of a record from prev to cur, where either of them is nil
+// if the record does not exist
+func (f family[T, P]) emit(ctx contractapi.TransactionContextInterface, op string, prev, cur P) error {
+	e := Event{Kind: f.store.kind, Operation: op, Version: EventVersion}
+	if prev != nil {
+		e.ID, e.OldOwner = prev.id(), prev.owner()
+	}
+
+	if cur != nil {
+		e.ID, e.NewOwner = cur.id(), cur.owner()
+	}
+
+	var err error
+	if e.Changed, err = changedFields(prev, cur); err != nil {
+		return fmt.Errorf(`comparing %s %d failed - %w`, f.store.kind, e.ID, err)
+	}
+
+	return emitEvent(ctx, e)
+}
+
+// emitEvent buffers the event until the transaction succeeds, or sets it right away for
+// contexts which do not buffer events
+func emitEvent(ctx contractapi.TransactionContextInterface, e Event) error {
+	if tc, ok := ctx.(*txContext); ok {
+		tc.events = append(tc.events, e)
+		return nil
+	}
+
+	return setEvent(ctx, e.Operation, e)
+}
+
+func setEvent(ctx contractapi.TransactionContextInterface, name string, payload interface{}) error {
+	byts, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf(`marshal %s event failed - %w`, name, err)
+	}
+
+	if err = ctx.GetStub().SetEvent(name, byts); err != nil {
+		return fmt.Errorf(`set %s event failed - %w`, name, err)
+	}
+
+	return nil
+}
+
+// changedFields returns the sorted JSON fields differing between two versions of a record,
+// leaving out the audit stamps and the version which change on every write
+func changedFields(prev, cur interface{}) ([]string, error) {
+	var fields [2]map[string]json.RawMessage
+	for i, r := range []interface{}{prev, cur} {
+		byts, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// a nil record is encoded as null which leaves the map empty
+		if err = json.Unmarshal(byts, &fields[i]); err != nil {
+			return nil, err
+		}
+
+		for _, name := range auditFields {
+			delete(fields[i], name)
+		}
+		delete(fields[i], `version`)
+	}
+
+	changed := []string{}
+	for name, val := range fields[0] {
+		if next, ok := fields[1][name]; !ok || !bytes.Equal(val, next) {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range fields[1] {
+		if _, ok := fields[0][name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
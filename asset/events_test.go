@@ -0,0 +1,78 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"reflect"
+	"testing"
+)
+
+func nextEvent(stub *shimtest.MockStub, name string, payload interface{}, t *testing.T) {
+	select {
+	case e := <-stub.ChaincodeEventsChannel:
+		if e.EventName != name {
+			t.Fatalf(errExpect, name, e.EventName)
+		}
+
+		if err := json.Unmarshal(e.Payload, payload); err != nil {
+			t.Fatalf("failed to unmarshal event - %s", err.Error())
+		}
+	default:
+		t.Fatalf(`expected %s event`, name)
+	}
+}
+
+func TestSmartContractAssetEvents(t *testing.T) {
+	stub := newMockStub()
+	testCreate(stub, t)
+
+	var e Event
+	nextEvent(stub, EventAssetCreated, &e, t)
+	in := Event{Changed: []string{"color", "docType", "id", "owner", "value"}, ID: testAsset.ID, Kind: kindAsset,
+		NewOwner: testAsset.Owner, Operation: EventAssetCreated, Version: EventVersion}
+	if !reflect.DeepEqual(in, e) {
+		t.Fatalf(errExpect, marshal(in, t), marshal(e, t))
+	}
+
+	if res := stub.MockInvoke(`1`, toArgs([]string{"TransferVehicle", "1", ownrDavid})); res.Status == shim.OK {
+		t.Fatalf(`transferring a missing vehicle should fail`)
+	}
+
+	if len(stub.ChaincodeEventsChannel) != 0 {
+		t.Fatalf(`failed transactions should not emit events`)
+	}
+
+	if res := stub.MockInvoke(`2`, toArgs([]string{"TransferAsset", "88", ownrDavid})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	e = Event{}
+	nextEvent(stub, EventAssetTransferred, &e, t)
+	in = Event{Changed: []string{"owner"}, ID: testAsset.ID, Kind: kindAsset, NewOwner: ownrDavid,
+		OldOwner: testAsset.Owner, Operation: EventAssetTransferred, Version: EventVersion}
+	if !reflect.DeepEqual(in, e) {
+		t.Fatalf(errExpect, marshal(in, t), marshal(e, t))
+	}
+}
+
+func TestSmartContractInitLedgerEvents(t *testing.T) {
+	stub := newMockStub()
+	testInitLedger(stub, t)
+
+	var b BatchEvent
+	nextEvent(stub, EventAssetBatch, &b, t)
+	if len(b.Events) != len(assets) || b.Version != EventVersion {
+		t.Fatalf(`expected a batch of %d events, got %s`, len(assets), marshal(b, t))
+	}
+
+	for i, e := range b.Events {
+		if e.Operation != EventAssetCreated || e.ID != assets[i].ID {
+			t.Fatalf(`unexpected event %s`, marshal(e, t))
+		}
+	}
+
+	if len(stub.ChaincodeEventsChannel) != 0 {
+		t.Fatalf(`a transaction should emit a single event`)
+	}
+}
@@ -0,0 +1,342 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// family implements the transactions shared by every asset kind once, so that the
+// SmartContract only has to expose them under the transaction names of each kind
+type family[T any, P record[T]] struct {
+	store store[T, P]
+}
+
+// kindFamily is the kind agnostic view of a family, used by transactions which receive the kind as an argument
+type kindFamily interface {
+	update(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string, version int) error
+	restore(ctx contractapi.TransactionContextInterface, id int) error
+	purge(ctx contractapi.TransactionContextInterface, id int) error
+	archived(ctx contractapi.TransactionContextInterface, pageSize int, bookmark string) (*Page, error)
+	transfer(ctx contractapi.TransactionContextInterface, id int, newOwner string, version int) error
+	createBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error)
+	updateBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error)
+	transferBatch(ctx contractapi.TransactionContextInterface, itemsJSON string, limit int) (*BatchReport, error)
+	page(ctx contractapi.TransactionContextInterface, pageSize int, bookmark string) (*Page, error)
+	query(ctx contractapi.TransactionContextInterface, selector string, pageSize int, bookmark string) (*Page, error)
+	queryByTimestamp(ctx contractapi.TransactionContextInterface, field, from, to string, pageSize int, bookmark string) (*Page, error)
+	queryByOwner(ctx contractapi.TransactionContextInterface, owner string, pageSize int, bookmark string) (*Page, error)
+	queryByColor(ctx contractapi.TransactionContextInterface, clr string, pageSize int, bookmark string) (*Page, error)
+	history(ctx contractapi.TransactionContextInterface, id int, pageSize int, bookmark string) (*HistoryPage, error)
+	exists(ctx contractapi.TransactionContextInterface, id int) (bool, error)
+	putEncoded(ctx contractapi.TransactionContextInterface, id int, byts []byte) error
+	importRecord(ctx contractapi.TransactionContextInterface, byts []byte) error
+	rebuildIndexes(ctx contractapi.TransactionContextInterface) (int, error)
+	endorsingOrgs(ctx contractapi.TransactionContextInterface, id int) ([]string, error)
+	resetEndorsement(ctx contractapi.TransactionContextInterface, id int) error
+	issueShares(ctx contractapi.TransactionContextInterface, id int, units int) error
+	transferShares(ctx contractapi.TransactionContextInterface, id int, to string, units int) error
+	redeemShares(ctx contractapi.TransactionContextInterface, id int) error
+	getShares(ctx contractapi.TransactionContextInterface, id int) (*ShareLedger, error)
+}
+
+func familyOf(kind string) (kindFamily, error) {
+	switch kind {
+	case kindAsset:
+		return assetFamily, nil
+	case kindVehicle:
+		return vehicleFamily, nil
+	case kindBook:
+		return bookFamily, nil
+	case kindHouse:
+		return houseFamily, nil
+	default:
+		return nil, errorf(CodeInvalid, `unknown asset kind %s`, kind)
+	}
+}
+
+func newFamily[T any, P record[T]](kind string) family[T, P] {
+	return family[T, P]{store: store[T, P]{kind: kind}}
+}
+
+func (f family[T, P]) create(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	args := &validator{}
+	r := P(new(T))
+	r.setID(id)
+	r.setColor(color)
+	r.setOwner(owner)
+	r.setValue(args.value(val))
+
+	return f.createRecord(ctx, r, args.violations...)
+}
+
+// createRecord stores a fully populated and valid record given that its id is not taken yet,
+// where the owner defaults to the invoker and only admins may assign records to others. The
+// violations of the arguments the record was built from are reported along with its own.
+func (f family[T, P]) createRecord(ctx contractapi.TransactionContextInterface, r P, args ...Violation) error {
+	owner, err := ownerOnCreate(ctx, r.owner())
+	if err != nil {
+		return fmt.Errorf(`create %s failed - %w`, f.store.kind, err)
+	}
+	r.setOwner(owner)
+	r.setVersion(1)
+
+	if err = f.stamp(ctx, r, true); err != nil {
+		return err
+	}
+
+	if err = f.validate(r, args...); err != nil {
+		return err
+	}
+
+	exists, err := f.store.exists(ctx, r.id())
+	if err != nil {
+		return fmt.Errorf(`create %s failed - %w`, f.store.kind, err)
+	}
+
+	if exists {
+		return errorf(CodeAlreadyExists, `%s with id %d already exists`, f.store.kind, r.id())
+	}
+
+	if err = f.endorse(ctx, r); err != nil {
+		return err
+	}
+
+	if err = f.store.put(ctx, r); err != nil {
+		return err
+	}
+
+	return f.emit(ctx, EventAssetCreated, nil, r)
+}
+
+func (f family[T, P]) get(ctx contractapi.TransactionContextInterface, id int) (*T, error) {
+	return f.store.get(ctx, id)
+}
+
+// anyVersion skips the check of the version of a record on changes
+const anyVersion = -1
+
+// atVersion fails with a conflict unless the record is at the expected version
+func (f family[T, P]) atVersion(r P, version int) error {
+	if version != anyVersion && r.version() != version {
+		return errorf(CodeConflict, `%s %d is at version %d instead of %d`, f.store.kind, r.id(), r.version(), version)
+	}
+
+	return nil
+}
+
+// update overwrites the attributes common to all kinds while retaining the kind specific
+// ones, given that the record is still at the expected version
+func (f family[T, P]) update(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string, version int) error {
+	args := &validator{}
+	value := args.value(val)
+
+	return f.modify(ctx, id, EventAssetUpdated, func(r P) error {
+		if err := f.atVersion(r, version); err != nil {
+			return err
+		}
+
+		r.setColor(color)
+		r.setValue(value)
+		if len(args.violations) > 0 {
+			r.setOwner(owner)
+			return f.validate(r, args.violations...)
+		}
+
+		if owner == r.owner() {
+			return nil
+		}
+
+		if err := f.unfractionalised(ctx, id); err != nil {
+			return err
+		}
+
+		return f.handOver(ctx, owner)(r)
+	})
+}
+
+// delete moves a record into the archive of its kind, from which it may be restored or purged
+func (f family[T, P]) delete(ctx contractapi.TransactionContextInterface, id int) error {
+	r, err := f.store.get(ctx, id)
+	if err != nil {
+		return fmt.Errorf(`get %s failed - %w`, f.store.kind, err)
+	}
+
+	if err = authorizeOwner(ctx, P(r).owner()); err != nil {
+		return fmt.Errorf(`delete %s %d failed - %w`, f.store.kind, id, err)
+	}
+
+	if err = f.transferable(ctx, id); err != nil {
+		return err
+	}
+
+	if err = f.archive(ctx, r); err != nil {
+		return err
+	}
+
+	if err = f.store.del(ctx, id); err != nil {
+		return err
+	}
+
+	return f.emit(ctx, EventAssetDeleted, r, nil)
+}
+
+// modify applies fn on the stored record of the given id and writes the result back,
+// given that the invoker is the owner of the record or an admin and that it is not
+// locked, and reports it as op
+func (f family[T, P]) modify(ctx contractapi.TransactionContextInterface, id int, op string, fn func(r P) error) error {
+	return f.change(ctx, id, op, func(r P) error {
+		if err := authorizeOwner(ctx, r.owner()); err != nil {
+			return fmt.Errorf(`modify %s %d failed - %w`, f.store.kind, id, err)
+		}
+
+		if err := f.unlocked(ctx, id); err != nil {
+			return err
+		}
+
+		return fn(r)
+	})
+}
+
+// change applies fn on the stored record of the given id and writes the result back as its
+// next version given that it is still valid, without any further checks, which are up to the caller
+func (f family[T, P]) change(ctx contractapi.TransactionContextInterface, id int, op string, fn func(r P) error) error {
+	r, err := f.store.get(ctx, id)
+	if err != nil {
+		return fmt.Errorf(`get %s failed - %w`, f.store.kind, err)
+	}
+
+	prev := *r
+	if err = fn(r); err != nil {
+		return err
+	}
+
+	P(r).setVersion(P(r).version() + 1)
+	if err = f.stamp(ctx, r, false); err != nil {
+		return err
+	}
+
+	if err = f.validate(r); err != nil {
+		return err
+	}
+
+	if err = f.store.put(ctx, r); err != nil {
+		return err
+	}
+
+	return f.emit(ctx, op, &prev, r)
+}
+
+// transfer hands a record over to a new owner given that it is still at the expected version
+func (f family[T, P]) transfer(ctx contractapi.TransactionContextInterface, id int, newOwner string, version int) error {
+	return f.modify(ctx, id, EventAssetTransferred, func(r P) error {
+		if err := f.atVersion(r, version); err != nil {
+			return err
+		}
+
+		if err := f.unfractionalised(ctx, id); err != nil {
+			return err
+		}
+
+		return f.handOver(ctx, newOwner)(r)
+	})
+}
+
+// transferable fails if the record can not be handed over as a whole, as it is locked or fractionalised
+func (f family[T, P]) transferable(ctx contractapi.TransactionContextInterface, id int) error {
+	if err := f.unlocked(ctx, id); err != nil {
+		return err
+	}
+
+	return f.unfractionalised(ctx, id)
+}
+
+// handOver returns the change of a record to a new owner, whose org then endorses its changes,
+// revoking the approval of its token granted by the previous owner
+func (f family[T, P]) handOver(ctx contractapi.TransactionContextInterface, newOwner string) func(r P) error {
+	return func(r P) error {
+		r.setOwner(newOwner)
+		if err := f.revokeApproval(ctx, r.id()); err != nil {
+			return err
+		}
+
+		return f.endorse(ctx, r)
+	}
+}
+
+func (f family[T, P]) changeColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
+	return f.modify(ctx, id, EventAssetColourChanged, func(r P) error {
+		r.setColor(clr)
+		return nil
+	})
+}
+
+func (f family[T, P]) changeValue(ctx contractapi.TransactionContextInterface, id int, val string) error {
+	args := &validator{}
+	value := args.value(val)
+
+	return f.modify(ctx, id, EventAssetValueChanged, func(r P) error {
+		r.setValue(value)
+		if len(args.violations) > 0 {
+			return f.validate(r, args.violations...)
+		}
+
+		return nil
+	})
+}
+
+func (f family[T, P]) getAll(ctx contractapi.TransactionContextInterface, limit int) ([]*T, error) {
+	return f.store.all(ctx, limit)
+}
+
+func (f family[T, P]) page(ctx contractapi.TransactionContextInterface, pageSize int, bookmark string) (*Page, error) {
+	return f.store.page(ctx, pageSize, bookmark)
+}
+
+func (f family[T, P]) exists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	return f.store.exists(ctx, id)
+}
+
+func (f family[T, P]) history(ctx contractapi.TransactionContextInterface, id int, pageSize int, bookmark string) (*HistoryPage, error) {
+	return f.store.history(ctx, id, pageSize, bookmark)
+}
+
+func (f family[T, P]) query(ctx contractapi.TransactionContextInterface, selector string, pageSize int, bookmark string) (*Page, error) {
+	return f.store.querySelector(ctx, selector, pageSize, bookmark)
+}
+
+func (f family[T, P]) queryByOwner(ctx contractapi.TransactionContextInterface, owner string, pageSize int, bookmark string) (*Page, error) {
+	return f.store.queryByField(ctx, `owner`, owner, ownerIndex, pageSize, bookmark)
+}
+
+func (f family[T, P]) queryByColor(ctx contractapi.TransactionContextInterface, clr string, pageSize int, bookmark string) (*Page, error) {
+	return f.store.queryByField(ctx, `color`, clr, colorIndex, pageSize, bookmark)
+}
+
+func (f family[T, P]) byOwner(ctx contractapi.TransactionContextInterface, owner string, limit int) ([]*T, error) {
+	return f.store.byIndex(ctx, ownerIndexKey, owner, limit)
+}
+
+func (f family[T, P]) byColor(ctx contractapi.TransactionContextInterface, clr string, limit int) ([]*T, error) {
+	return f.store.byIndex(ctx, colorIndexKey, clr, limit)
+}
+
+func (f family[T, P]) rebuildIndexes(ctx contractapi.TransactionContextInterface) (int, error) {
+	return f.store.rebuildIndexes(ctx)
+}
+
+// putEncoded stores a JSON encoded record of the kind under the given id, endorsed by the org
+// of its owner
+func (f family[T, P]) putEncoded(ctx contractapi.TransactionContextInterface, id int, byts []byte) error {
+	r := P(new(T))
+	if err := json.Unmarshal(byts, r); err != nil {
+		return fmt.Errorf(`unmarshal %s failed for %s %d - %w`, f.store.kind, f.store.kind, id, err)
+	}
+	r.setID(id)
+
+	if err := f.store.put(ctx, r); err != nil {
+		return err
+	}
+
+	return f.endorse(ctx, r)
+}
@@ -0,0 +1,84 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"time"
+)
+
+// HistoryEntry is a version of a record as written by a past transaction. Record
+// holds the decoded record of the asset kind and is empty for deletions.
+type HistoryEntry struct {
+	IsDelete  bool        `json:"isDelete"`
+	Record    interface{} `json:"record"`
+	Timestamp time.Time   `json:"timestamp"`
+	TxID      string      `json:"txId"`
+}
+
+// HistoryPage is a page of history entries ordered from the most recent to the oldest.
+// Bookmark is the transaction ID to resume from and is empty on the last page.
+type HistoryPage struct {
+	Bookmark string         `json:"bookmark"`
+	Entries  []HistoryEntry `json:"entries"`
+}
+
+// history pages through GetHistoryForKey which has no native pagination, hence the
+// bookmark is the transaction ID of the last entry of the previous page
+func (s store[T, P]) history(ctx contractapi.TransactionContextInterface, id int, pageSize int, bookmark string) (*HistoryPage, error) {
+	if err := validPageSize(pageSize); err != nil {
+		return nil, err
+	}
+
+	k, err := key(ctx, s.kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := ctx.GetStub().GetHistoryForKey(k)
+	if err != nil {
+		return nil, fmt.Errorf(`get history failed for %s %d - %w`, s.kind, id, err)
+	}
+	defer itr.Close()
+
+	page := &HistoryPage{Entries: []HistoryEntry{}}
+	skipping := bookmark != ``
+	for itr.HasNext() {
+		mod, err := itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf(`iterating next %s history entry failed - %w`, s.kind, err)
+		}
+
+		if skipping {
+			skipping = mod.TxId != bookmark
+			continue
+		}
+
+		if len(page.Entries) == pageSize {
+			page.Bookmark = page.Entries[pageSize-1].TxID
+			break
+		}
+
+		entry := HistoryEntry{
+			IsDelete:  mod.IsDelete,
+			Timestamp: mod.Timestamp.AsTime(),
+			TxID:      mod.TxId,
+		}
+
+		if !mod.IsDelete {
+			var r T
+			if err = json.Unmarshal(mod.Value, &r); err != nil {
+				return nil, fmt.Errorf(`unmarshal %s failed for transaction %s - %w`, s.kind, mod.TxId, err)
+			}
+			entry.Record = &r
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	if skipping {
+		return nil, errorf(CodeInvalid, `bookmark %s is not in the history of %s %d`, bookmark, s.kind, id)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,82 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSmartContractGetAssetHistory(t *testing.T) {
+	stub := newQueryStub()
+	created := Asset{Color: clrBlue, ID: 5, Owner: "Jane Doe", Value: euros(100)}
+	transferred := Asset{Color: clrBlue, ID: 5, Owner: ownrDavid, Value: euros(100)}
+
+	stub.history[compositeKey(kindAsset, created.ID, t)] = []*queryresult.KeyModification{
+		{TxId: `tx3`, IsDelete: true, Timestamp: timestamppb.New(time.Unix(300, 0))},
+		{TxId: `tx2`, Value: marshal(transferred, t), Timestamp: timestamppb.New(time.Unix(200, 0))},
+		{TxId: `tx1`, Value: marshal(created, t), Timestamp: timestamppb.New(time.Unix(100, 0))},
+	}
+
+	first := getHistory(stub, created.ID, 2, ``, t)
+	if len(first.Entries) != 2 || first.Bookmark != `tx2` {
+		t.Fatalf(`expected 2 entries with bookmark tx2, got %d entries with bookmark %s`, len(first.Entries), first.Bookmark)
+	}
+
+	if !first.Entries[0].IsDelete || first.Entries[0].Record != nil {
+		t.Fatalf(`expected the latest entry to be a deletion, got %v`, first.Entries[0])
+	}
+
+	second := getHistory(stub, created.ID, 2, first.Bookmark, t)
+	if len(second.Entries) != 1 || second.Bookmark != `` {
+		t.Fatalf(`expected the last entry without a bookmark, got %d entries with bookmark %s`, len(second.Entries), second.Bookmark)
+	}
+
+	entry := second.Entries[0]
+	if entry.TxID != `tx1` || !entry.Timestamp.Equal(time.Unix(100, 0)) {
+		t.Fatalf(errExpect, `tx1`, entry.TxID)
+	}
+
+	rec, err := json.Marshal(entry.Record)
+	if err != nil {
+		t.Fatalf("failed to marshal record - %s", err.Error())
+	}
+
+	if string(rec) != string(marshal(created, t)) {
+		t.Fatalf(errExpect, marshal(created, t), rec)
+	}
+}
+
+func TestSmartContractGetAssetHistoryUnknownBookmark(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "GetAssetHistory", kindAsset, "5", "10", "tx9"); res.Status == shim.OK {
+		t.Fatalf(`history with an unknown bookmark should fail`)
+	}
+}
+
+func getHistory(stub *queryStub, id, pageSize int, bookmark string, t *testing.T) HistoryPage {
+	res := stub.invoke(`1`, "GetAssetHistory", kindAsset, strconv.Itoa(id), strconv.Itoa(pageSize), bookmark)
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// records are decoded as generic JSON objects on the client side
+	var page HistoryPage
+	if err := json.Unmarshal(res.Payload, &page); err != nil {
+		t.Fatalf("failed to unmarshal history - %s", err.Error())
+	}
+
+	return page
+}
+
+func marshal(v interface{}, t *testing.T) []byte {
+	byts, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal - %s", err.Error())
+	}
+
+	return byts
+}
@@ -0,0 +1,115 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+var houseFamily = newFamily[House](kindHouse)
+
+// House attributes are defined in alphabetical order to make JSON struct deterministic.
+// Area is recorded in square metres.
+type House struct {
+	Address   string `json:"address"`
+	Area      int    `json:"area"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"createdAt"`
+	CreatedBy string `json:"createdBy"`
+	DocType   string `json:"docType"`
+	ID        int    `json:"id"`
+	Owner     string `json:"owner"`
+	Rooms     int    `json:"rooms"`
+	UpdatedAt string `json:"updatedAt"`
+	UpdatedBy string `json:"updatedBy"`
+	Value     Money  `json:"value"`
+	Version   int    `json:"version"`
+}
+
+func (s *SmartContract) CreateHouse(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return houseFamily.create(ctx, color, id, owner, val)
+}
+
+func (s *SmartContract) GetHouse(ctx contractapi.TransactionContextInterface, id int) (*House, error) {
+	return houseFamily.get(ctx, id)
+}
+
+func (s *SmartContract) UpdateHouse(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return houseFamily.update(ctx, color, id, owner, val, anyVersion)
+}
+
+// CreateHouseWithDetails creates a house along with the attributes specific to houses
+func (s *SmartContract) CreateHouseWithDetails(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string, address string, area int, rooms int) error {
+	args := &validator{}
+	h := &House{Address: address, Area: area, Color: color, ID: id, Owner: owner, Rooms: rooms, Value: args.value(val)}
+
+	return houseFamily.createRecord(ctx, h, args.violations...)
+}
+
+// UpdateHouseDetails replaces the attributes specific to houses of an existing house
+func (s *SmartContract) UpdateHouseDetails(ctx contractapi.TransactionContextInterface, id int, address string, area int, rooms int) error {
+	return houseFamily.modify(ctx, id, EventAssetUpdated, func(h *House) error {
+		h.Address = address
+		h.Area = area
+		h.Rooms = rooms
+
+		return nil
+	})
+}
+
+func (s *SmartContract) DeleteHouse(ctx contractapi.TransactionContextInterface, id int) error {
+	return houseFamily.delete(ctx, id)
+}
+
+func (s *SmartContract) TransferHouse(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
+	return houseFamily.transfer(ctx, id, newOwner, anyVersion)
+}
+
+func (s *SmartContract) GetAllHouses(ctx contractapi.TransactionContextInterface) ([]*House, error) {
+	return houseFamily.getAll(ctx, s.maxQueryResults())
+}
+
+// GetHousesByOwner returns the houses of an owner from the owner index, which also works on LevelDB
+func (s *SmartContract) GetHousesByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*House, error) {
+	return houseFamily.byOwner(ctx, owner, s.maxQueryResults())
+}
+
+func (s *SmartContract) GetHousesByColor(ctx contractapi.TransactionContextInterface, clr string) ([]*House, error) {
+	return houseFamily.byColor(ctx, clr, s.maxQueryResults())
+}
+
+func (s *SmartContract) HouseExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	return houseFamily.exists(ctx, id)
+}
+
+func (s *SmartContract) ChangeHouseColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
+	return houseFamily.changeColour(ctx, id, clr)
+}
+
+func (s *SmartContract) ChangeHouseValue(ctx contractapi.TransactionContextInterface, id int, val string) error {
+	return houseFamily.changeValue(ctx, id, val)
+}
+
+// check applies the rules of houses once any of their details is given, as houses created
+// without details only carry the common attributes
+func (h *House) check(v *validator) {
+	if h.Address == `` && h.Area == 0 && h.Rooms == 0 {
+		return
+	}
+
+	v.text(`address`, h.Address, maxTextLength)
+	v.check(h.Area > 0, `area`, `area %d should be a positive number of square metres`, h.Area)
+	v.check(h.Rooms > 0, `rooms`, `rooms %d should be a positive number`, h.Rooms)
+}
+
+func (h *House) id() int                  { return h.ID }
+func (h *House) setID(id int)             { h.ID = id }
+func (h *House) owner() string            { return h.Owner }
+func (h *House) setOwner(owner string)    { h.Owner = owner }
+func (h *House) color() string            { return h.Color }
+func (h *House) setColor(clr string)      { h.Color = clr }
+func (h *House) value() Money             { return h.Value }
+func (h *House) setValue(val Money)       { h.Value = val }
+func (h *House) setCreated(at, by string) { h.CreatedAt, h.CreatedBy = at, by }
+func (h *House) setUpdated(at, by string) { h.UpdatedAt, h.UpdatedBy = at, by }
+func (h *House) version() int             { return h.Version }
+func (h *House) setVersion(ver int)       { h.Version = ver }
+func (h *House) setDocType(kind string)   { h.DocType = kind }
@@ -1,7 +1,6 @@
 package asset
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
@@ -12,20 +11,22 @@ import (
 )
 
 var (
-	testHouse = Asset{Color: "brown", ID: 88, Owner: "Arnold", Value: 989}
+	testHouse = House{Color: "brown", DocType: kindHouse, ID: 88, Owner: "Arnold", Value: euros(989)}
 )
 
 func TestSmartContractGetAllHouses(t *testing.T) {
 	stub := newMockStub()
 	testInitLedger(stub, t)
+	testCreateHouse(stub, t)
 
+	// assets seeded by the ledger initialisation must not leak into the house namespace
 	res := stub.MockInvoke(`2`, [][]byte{[]byte("GetAllHouses")})
 	if res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
 	in := marshalHouses()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -40,7 +41,7 @@ func TestSmartContractGetHouse(t *testing.T) {
 	}
 
 	in := marshalHouse()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -50,13 +51,14 @@ func TestSmartContractUpdateHouse(t *testing.T) {
 	testCreateHouse(stub, t)
 
 	testHouse.Color = clrBlue
-	testHouse.Value = 1500
+	testHouse.Value = euros(1500)
 	testUpdateHouse(stub, t)
+	testHouse.Version = 2
 
 	in := marshalHouse()
 	out := getHouseState(stub, testHouse.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -72,9 +74,10 @@ func TestSmartContractTransferHouse(t *testing.T) {
 	}
 
 	testHouse.Owner = ownrDavid
+	testHouse.Version = 2
 	out := getHouseState(stub, testHouse.ID, t)
 	in := marshalHouse()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -90,9 +93,10 @@ func TestSmartContractChangeHouseColour(t *testing.T) {
 	}
 
 	testHouse.Color = clrBrown
+	testHouse.Version = 2
 	out := getHouseState(stub, testHouse.ID, t)
 	in := marshalHouse()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -104,7 +108,7 @@ func TestSmartContractCreateHouse(t *testing.T) {
 	in := marshalHouse()
 	out := getHouseState(stub, testHouse.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -119,10 +123,11 @@ func TestSmartContractChangeHouseValue(t *testing.T) {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
-	testHouse.Value = 888
+	testHouse.Value = euros(888)
+	testHouse.Version = 2
 	out := getHouseState(stub, testHouse.ID, t)
 	in := marshalHouse()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -143,8 +148,41 @@ func TestSmartContractDeleteHouse(t *testing.T) {
 	}
 }
 
+func TestSmartContractCreateHouseWithDetails(t *testing.T) {
+	stub := newMockStub()
+	h := House{Address: "Calle Mayor 1, Pamplona", Area: 120, Color: clrBlue, DocType: kindHouse, ID: 101, Owner: ownrDavid, Rooms: 4, Value: euros(250000), Version: 1}
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateHouseWithDetails"), []byte(h.Color), []byte(strconv.Itoa(h.ID)), []byte(h.Owner), []byte(h.Value.String()),
+		[]byte(h.Address), []byte(strconv.Itoa(h.Area)), []byte(strconv.Itoa(h.Rooms)),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	in, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("failed to marshal house - %s", err.Error())
+	}
+
+	out := getHouseState(stub, h.ID, t)
+	if !sameRecords(in, out, t) {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractUpdateHouseWithInvalidArea(t *testing.T) {
+	stub := newMockStub()
+	testCreateHouse(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("UpdateHouseDetails"), []byte(strconv.Itoa(testHouse.ID)), []byte("Calle Mayor 1"), []byte("0"), []byte("3"),
+	}); res.Status == shim.OK {
+		t.Fatalf(`house with no area should be rejected`)
+	}
+}
+
 func getHouseState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
-	out, err := stub.GetState(strconv.Itoa(id))
+	out, err := stub.GetState(compositeKey(kindHouse, id, t))
 	if err != nil {
 		t.Fatalf("failed to retrieve asset info - %s", err.Error())
 	}
@@ -153,7 +191,7 @@ func getHouseState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
 }
 
 func marshalHouses() []byte {
-	byts, err := json.Marshal(assets)
+	byts, err := json.Marshal([]House{testHouse})
 	if err != nil {
 		log.Fatal(fmt.Sprintf("failed to marshal assets - %s", err.Error()))
 	}
@@ -163,10 +201,12 @@ func marshalHouses() []byte {
 
 func testCreateHouse(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`4`, [][]byte{
-		[]byte("CreateHouse"), []byte(testHouse.Color), []byte(strconv.Itoa(testHouse.ID)), []byte(testHouse.Owner), []byte(strconv.Itoa(testHouse.Value)),
+		[]byte("CreateHouse"), []byte(testHouse.Color), []byte(strconv.Itoa(testHouse.ID)), []byte(testHouse.Owner), []byte(testHouse.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
+
+	testHouse.Version = 1
 }
 
 func marshalHouse() []byte {
@@ -180,7 +220,7 @@ func marshalHouse() []byte {
 
 func testUpdateHouse(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`5`, [][]byte{
-		[]byte("UpdateHouse"), []byte(testHouse.Color), []byte(strconv.Itoa(testHouse.ID)), []byte(testHouse.Owner), []byte(strconv.Itoa(testHouse.Value)),
+		[]byte("UpdateHouse"), []byte(testHouse.Color), []byte(strconv.Itoa(testHouse.ID)), []byte(testHouse.Owner), []byte(testHouse.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
@@ -0,0 +1,234 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// lockKey is the object type of the hash time locks, keyed by the kind and id of the record
+const lockKey = `lock~kind~id`
+
+// Lock is a hash time lock, which hands a record over to the receiver presenting the
+// preimage of the hashlock before the timeout, and otherwise lets the sender keep it
+type Lock struct {
+	Hashlock string `json:"hashlock"`
+	ID       int    `json:"id"`
+	Receiver string `json:"receiver"`
+	Sender   string `json:"sender"`
+	// Timeout is the unix time in seconds after which the lock can only be refunded
+	Timeout int64 `json:"timeout"`
+}
+
+func (f family[T, P]) lockKey(ctx contractapi.TransactionContextInterface, id int) (string, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(lockKey, []string{f.store.kind, strconv.Itoa(id)})
+	if err != nil {
+		return ``, fmt.Errorf(`create lock key failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return k, nil
+}
+
+// findLock returns the lock of a record or nil if it is not locked
+func (f family[T, P]) findLock(ctx contractapi.TransactionContextInterface, id int) (*Lock, error) {
+	k, err := f.lockKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	byts, err := ctx.GetStub().GetState(k)
+	if err != nil {
+		return nil, fmt.Errorf(`get lock failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	if byts == nil {
+		return nil, nil
+	}
+
+	var l Lock
+	if err = json.Unmarshal(byts, &l); err != nil {
+		return nil, fmt.Errorf(`unmarshal lock failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return &l, nil
+}
+
+func (f family[T, P]) getLock(ctx contractapi.TransactionContextInterface, id int) (*Lock, error) {
+	l, err := f.findLock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil {
+		return nil, errorf(CodeNotFound, `%s %d is not locked`, f.store.kind, id)
+	}
+
+	return l, nil
+}
+
+// unlocked fails if the record is locked, as it may only be claimed or refunded then
+func (f family[T, P]) unlocked(ctx contractapi.TransactionContextInterface, id int) error {
+	l, err := f.findLock(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if l != nil {
+		return errorf(CodeConflict, `%s %d is locked until it is claimed or refunded`, f.store.kind, id)
+	}
+
+	return nil
+}
+
+func (f family[T, P]) lock(ctx contractapi.TransactionContextInterface, id int, hashlock, receiver string, timeout int64) error {
+	r, err := f.store.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller != P(r).owner() {
+		return errorf(CodeForbidden, `only the owner can lock %s %d`, f.store.kind, id)
+	}
+
+	if err = f.transferable(ctx, id); err != nil {
+		return err
+	}
+
+	hash, err := hex.DecodeString(hashlock)
+	if err != nil || len(hash) != sha256.Size {
+		return errorf(CodeInvalid, `hashlock must be a hex encoded SHA-256 hash`)
+	}
+
+	if receiver == `` {
+		return errorf(CodeInvalid, `receiver of the lock is required`)
+	}
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	if timeout <= now {
+		return errorf(CodeInvalid, `timeout %d of the lock has already passed`, timeout)
+	}
+
+	// the hashlock is stored in the lowercase encoding claims compare the preimage with
+	return f.putLock(ctx, Lock{Hashlock: hex.EncodeToString(hash), ID: id, Receiver: receiver, Sender: caller, Timeout: timeout})
+}
+
+// claim hands the record over to the receiver of its lock given the preimage of the hashlock
+func (f family[T, P]) claim(ctx contractapi.TransactionContextInterface, id int, preimage string) error {
+	l, err := f.getLock(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now >= l.Timeout {
+		return errorf(CodeConflict, `lock of %s %d timed out and can only be refunded`, f.store.kind, id)
+	}
+
+	hash := sha256.Sum256([]byte(preimage))
+	if hex.EncodeToString(hash[:]) != l.Hashlock {
+		return errorf(CodeForbidden, `preimage does not match the hashlock of %s %d`, f.store.kind, id)
+	}
+
+	// the receiver is not the owner yet, so the lock itself authorizes the transfer
+	if err = f.change(ctx, id, EventAssetTransferred, f.handOver(ctx, l.Receiver)); err != nil {
+		return err
+	}
+
+	return f.deleteLock(ctx, id)
+}
+
+// refund releases the record to its owner once its lock timed out
+func (f family[T, P]) refund(ctx contractapi.TransactionContextInterface, id int) error {
+	l, err := f.getLock(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	if now < l.Timeout {
+		return errorf(CodeConflict, `lock of %s %d can not be refunded before %d`, f.store.kind, id, l.Timeout)
+	}
+
+	return f.deleteLock(ctx, id)
+}
+
+func (f family[T, P]) putLock(ctx contractapi.TransactionContextInterface, l Lock) error {
+	k, err := f.lockKey(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+
+	byts, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf(`marshal lock failed for %s %d - %w`, f.store.kind, l.ID, err)
+	}
+
+	if err = ctx.GetStub().PutState(k, byts); err != nil {
+		return fmt.Errorf(`put lock failed for %s %d - %w`, f.store.kind, l.ID, err)
+	}
+
+	return nil
+}
+
+func (f family[T, P]) deleteLock(ctx contractapi.TransactionContextInterface, id int) error {
+	k, err := f.lockKey(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().DelState(k); err != nil {
+		return fmt.Errorf(`delete lock failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return nil
+}
+
+// txTime returns the timestamp of the transaction in unix seconds, which all endorsers agree on
+func txTime(ctx contractapi.TransactionContextInterface) (int64, error) {
+	ts, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return 0, fmt.Errorf(`get transaction timestamp failed - %w`, err)
+	}
+
+	return ts.GetSeconds(), nil
+}
+
+// LockAsset locks the asset for the receiver until the timeout in unix seconds, where
+// hashlock is the hex encoded SHA-256 hash of a secret preimage
+func (s *SmartContract) LockAsset(ctx contractapi.TransactionContextInterface, id int, hashlock string, receiver string, timeout int64) error {
+	return assetFamily.lock(ctx, id, hashlock, receiver, timeout)
+}
+
+// ClaimAsset transfers a locked asset to its receiver given the preimage before the timeout
+func (s *SmartContract) ClaimAsset(ctx contractapi.TransactionContextInterface, id int, preimage string) error {
+	return assetFamily.claim(ctx, id, preimage)
+}
+
+// RefundAsset unlocks an asset for its owner after the timeout
+func (s *SmartContract) RefundAsset(ctx contractapi.TransactionContextInterface, id int) error {
+	return assetFamily.refund(ctx, id)
+}
+
+func (s *SmartContract) GetAssetLock(ctx contractapi.TransactionContextInterface, id int) (*Lock, error) {
+	return assetFamily.getLock(ctx, id)
+}
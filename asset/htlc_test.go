@@ -0,0 +1,140 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	preimage  = "secret"
+	lockStart = 1700000000
+	lockUntil = lockStart + 3600
+)
+
+func hashlock() string {
+	hash := sha256.Sum256([]byte(preimage))
+	return hex.EncodeToString(hash[:])
+}
+
+// testLock lets alice create asset 5 and lock it for bob
+func testLock(stub *queryStub, t *testing.T) string {
+	stub.now = &timestamp.Timestamp{Seconds: lockStart}
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub.MockStub, t)
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`2`, "LockAsset", "5", hashlock(), bob, strconv.Itoa(lockUntil)); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	return bob
+}
+
+func TestSmartContractLockedAssetRejectsChanges(t *testing.T) {
+	stub := newQueryStub()
+	testLock(stub, t)
+
+	for _, args := range [][]string{
+		{"TransferAsset", "5", ownrDavid},
+		{"ChangeAssetValue", "5", "1"},
+		{"UpdateAsset", clrBrown, "5", "", "1"},
+		{"LockAsset", "5", hashlock(), ownrDavid, strconv.Itoa(lockUntil)},
+	} {
+		if res := stub.invoke(`3`, args...); res.Status == shim.OK {
+			t.Fatalf(`%s of a locked asset should be rejected`, args[0])
+		}
+	}
+
+	stub.Creator = adminIdentity
+	if res := stub.invoke(`4`, "DeleteAsset", "5"); res.Status == shim.OK {
+		t.Fatalf(`deleting a locked asset should be rejected`)
+	}
+}
+
+func TestSmartContractClaimAsset(t *testing.T) {
+	stub := newQueryStub()
+	bob := testLock(stub, t)
+
+	if res := stub.invoke(`3`, "ClaimAsset", "5", "guess"); res.Status == shim.OK {
+		t.Fatalf(`claiming with a wrong preimage should fail`)
+	}
+
+	if res := stub.invoke(`4`, "RefundAsset", "5"); res.Status == shim.OK {
+		t.Fatalf(`refunding before the timeout should fail`)
+	}
+
+	stub.Creator = bobIdentity
+	if res := stub.invoke(`5`, "ClaimAsset", "5", preimage); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`6`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(res.Payload, &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if a.Owner != bob {
+		t.Fatalf(errExpect, bob, a.Owner)
+	}
+
+	// the asset is unlocked for its new owner
+	if res = stub.invoke(`7`, "ChangeAssetValue", "5", "1"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func TestSmartContractRefundAsset(t *testing.T) {
+	stub := newQueryStub()
+	testLock(stub, t)
+
+	stub.now = &timestamp.Timestamp{Seconds: lockUntil}
+	stub.Creator = bobIdentity
+	if res := stub.invoke(`3`, "ClaimAsset", "5", preimage); res.Status == shim.OK {
+		t.Fatalf(`claiming after the timeout should fail`)
+	}
+
+	if res := stub.invoke(`4`, "RefundAsset", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`5`, "ChangeAssetValue", "5", "1"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func TestSmartContractClaimAssetWithUppercaseHashlock(t *testing.T) {
+	stub := newQueryStub()
+	stub.now = &timestamp.Timestamp{Seconds: lockStart}
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub.MockStub, t)
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`2`, "LockAsset", "5", strings.ToUpper(hashlock()), bob, strconv.Itoa(lockUntil)); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = bobIdentity
+	if res := stub.invoke(`3`, "ClaimAsset", "5", preimage); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
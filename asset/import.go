@@ -0,0 +1,271 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// importKey is the transient map entry holding the rows of an import
+const importKey = `import`
+
+// formats of the rows of an import
+const (
+	formatCSV   = `csv`
+	formatJSONL = `jsonl`
+)
+
+// intColumns are the CSV columns of the kinds holding integers, which are encoded as
+// JSON numbers for the records to decode them
+var intColumns = map[string]bool{
+	`area`: true, `edition`: true, `id`: true, `mileage`: true, `rooms`: true, `version`: true, `year`: true,
+}
+
+// ImportRow is a row of an import which has not been imported, along with the reason
+type ImportRow struct {
+	ID     int    `json:"id"`
+	Kind   string `json:"kind"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportReport is the outcome of a chunk of an import. NextChunk is the chunk to resume
+// the import with and -1 once its last chunk has been imported.
+type ImportReport struct {
+	Chunk      int         `json:"chunk"`
+	Chunks     int         `json:"chunks"`
+	Duplicates []ImportRow `json:"duplicates"`
+	Imported   int         `json:"imported"`
+	NextChunk  int         `json:"nextChunk"`
+	Skipped    []ImportRow `json:"skipped"`
+}
+
+// importRow is a row of an import encoded as the JSON of a record of its kind, or the
+// reason it could not be parsed
+type importRow struct {
+	byts []byte
+	err  error
+	id   int
+	kind string
+	line int
+}
+
+// ImportAssets creates the records of the rows passed as CSV or JSON Lines in the import entry
+// of the transient map, keeping them out of the public arguments of the proposal. The fields
+// of the rows are the JSON fields of the records, where docType selects the kind and defaults
+// to asset. Rows are imported in chunks of at most MaxBatchSize rows, where chunk selects the
+// one to import, so that an import is resumed by passing the same rows again under the same
+// MaxBatchSize, which numbers the chunks. Rows whose record exists already are reported as
+// duplicates and invalid ones as skipped.
+func (s *SmartContract) ImportAssets(ctx contractapi.TransactionContextInterface, format string, chunk int) (*ImportReport, error) {
+	tm, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf(`get transient map failed - %w`, err)
+	}
+
+	in, ok := tm[importKey]
+	if !ok {
+		return nil, errorf(CodeInvalid, `%s is missing in the transient map`, importKey)
+	}
+
+	rows, err := parseImport(format, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errorf(CodeInvalid, `import has no rows`)
+	}
+
+	size := s.maxBatchSize()
+	chunks := (len(rows) + size - 1) / size
+	if chunk < 0 || chunk >= chunks {
+		return nil, errorf(CodeInvalid, `chunk %d should be between 0 and %d`, chunk, chunks-1)
+	}
+
+	report := &ImportReport{Chunk: chunk, Chunks: chunks, Duplicates: []ImportRow{}, NextChunk: chunk + 1, Skipped: []ImportRow{}}
+	if report.NextChunk == chunks {
+		report.NextChunk = -1
+	}
+
+	end := (chunk + 1) * size
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	// the writes of a transaction are not visible to its own reads, hence rows repeating
+	// a record of the same chunk are caught here
+	seen := make(map[string]bool)
+	for _, row := range rows[chunk*size : end] {
+		skip := ImportRow{ID: row.id, Kind: row.kind, Line: row.line}
+		if row.err != nil {
+			skip.Reason = row.err.Error()
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+
+		k := row.kind + `~` + strconv.Itoa(row.id)
+		if seen[k] {
+			skip.Reason = fmt.Sprintf(`%s with id %d is repeated in the import`, row.kind, row.id)
+			report.Duplicates = append(report.Duplicates, skip)
+			continue
+		}
+		seen[k] = true
+
+		f, err := familyOf(row.kind)
+		if err != nil {
+			skip.Reason = err.Error()
+			report.Skipped = append(report.Skipped, skip)
+			continue
+		}
+
+		if err = f.importRecord(ctx, row.byts); err == nil {
+			report.Imported++
+			continue
+		}
+
+		skip.Reason = err.Error()
+		switch CodeOf(err) {
+		case CodeInternal:
+			return nil, fmt.Errorf(`import of line %d failed - %w`, row.line, err)
+		case CodeAlreadyExists:
+			report.Duplicates = append(report.Duplicates, skip)
+		default:
+			report.Skipped = append(report.Skipped, skip)
+		}
+	}
+
+	return report, nil
+}
+
+// importRecord creates a record of the kind from its JSON encoding
+func (f family[T, P]) importRecord(ctx contractapi.TransactionContextInterface, byts []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(byts))
+	dec.DisallowUnknownFields()
+
+	r := P(new(T))
+	if err := dec.Decode(r); err != nil {
+		return errorf(CodeInvalid, `row does not match the %s records - %w`, f.store.kind, err)
+	}
+
+	return f.createRecord(ctx, r)
+}
+
+func parseImport(format string, in []byte) ([]importRow, error) {
+	switch format {
+	case formatCSV:
+		return parseCSV(in)
+	case formatJSONL:
+		return parseJSONL(in), nil
+	default:
+		return nil, errorf(CodeInvalid, `import format %s should be %s or %s`, format, formatCSV, formatJSONL)
+	}
+}
+
+// parseCSV reads rows whose header names the JSON fields of the records, where empty
+// cells leave the field unset
+func parseCSV(in []byte) ([]importRow, error) {
+	rd := csv.NewReader(bytes.NewReader(in))
+	header, err := rd.Read()
+	if err != nil {
+		return nil, errorf(CodeInvalid, `header of the CSV import can not be read - %w`, err)
+	}
+
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var rows []importRow
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			rows = append(rows, importRow{err: errorf(CodeInvalid, `row can not be read - %w`, err), line: perr.StartLine}.identified())
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf(`reading the CSV import failed - %w`, err)
+		}
+
+		line, _ := rd.FieldPos(0)
+		fields := make(map[string]interface{}, len(rec))
+		for i, cell := range rec {
+			cell = strings.TrimSpace(cell)
+			if cell == `` {
+				continue
+			}
+
+			switch {
+			case header[i] == `value`:
+				fields[header[i]], err = parseMoney(cell)
+			case intColumns[header[i]]:
+				fields[header[i]], err = strconv.Atoi(cell)
+			default:
+				fields[header[i]] = cell
+			}
+
+			if err != nil {
+				err = errorf(CodeInvalid, `column %s is malformed - %w`, header[i], err)
+				break
+			}
+		}
+
+		row := importRow{err: err, line: line}
+		if err == nil {
+			row.byts, row.err = json.Marshal(fields)
+		}
+		rows = append(rows, row.identified())
+	}
+
+	return rows, nil
+}
+
+// parseJSONL reads one JSON encoded record per line, where blank lines are ignored
+func parseJSONL(in []byte) []importRow {
+	var rows []importRow
+	for i, line := range bytes.Split(in, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		rows = append(rows, importRow{byts: line, line: i + 1}.identified())
+	}
+
+	return rows
+}
+
+// identified reads the kind and id of a row, defaulting to the asset kind
+func (r importRow) identified() importRow {
+	r.kind = kindAsset
+	if r.err != nil {
+		return r
+	}
+
+	var head struct {
+		DocType string `json:"docType"`
+		ID      int    `json:"id"`
+	}
+	if err := json.Unmarshal(r.byts, &head); err != nil {
+		r.err = errorf(CodeInvalid, `row is not a JSON object with a numeric id - %w`, err)
+		return r
+	}
+
+	if head.DocType != `` {
+		r.kind = head.DocType
+	}
+	r.id = head.ID
+
+	return r
+}
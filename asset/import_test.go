@@ -0,0 +1,114 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"strings"
+	"testing"
+)
+
+func importAssets(stub *queryStub, t *testing.T, format, chunk string) ImportReport {
+	res := stub.invoke(`1`, "ImportAssets", format, chunk)
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var report ImportReport
+	if err := json.Unmarshal(res.Payload, &report); err != nil {
+		t.Fatalf("failed to unmarshal import report - %s", err.Error())
+	}
+
+	return report
+}
+
+func TestSmartContractImportAssetsCSV(t *testing.T) {
+	stub := newQueryStub()
+	stub.TransientMap = map[string][]byte{importKey: []byte(strings.Join([]string{
+		`docType, id, color, owner, value, vin, make, model, year`,
+		`, 1, blue, David, 100 EUR, , , ,`,
+		`vehicle, 2, blue, David, 30000, YV1XZ16G3M2123456, Volvo, XC40, 2021`,
+		`asset, 1, red, David, 5, , , ,`,
+		`, 4, plaid, David, 5, , , ,`,
+		`, 5, blue, David, lots, , , ,`,
+		`ship, 6, blue, David, 5, , , ,`,
+		`, 7, blue, David, 5, YV1XZ16G3M2123456, , ,`,
+		`, 8, blue`,
+	}, "\n"))}
+
+	report := importAssets(stub, t, formatCSV, "0")
+	if report.Imported != 2 || report.Chunks != 1 || report.NextChunk != -1 {
+		t.Fatalf(`unexpected import report %s`, marshal(report, t))
+	}
+
+	if len(report.Duplicates) != 1 || report.Duplicates[0].Line != 4 || report.Duplicates[0].ID != 1 {
+		t.Fatalf(`unexpected duplicates %s`, marshal(report.Duplicates, t))
+	}
+
+	lines := []int{}
+	for _, row := range report.Skipped {
+		lines = append(lines, row.Line)
+	}
+
+	if in := []int{5, 6, 7, 8, 9}; string(marshal(lines, t)) != string(marshal(in, t)) {
+		t.Fatalf(errExpect, marshal(in, t), marshal(report.Skipped, t))
+	}
+
+	res := stub.invoke(`2`, "GetVehicle", "2")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var v Vehicle
+	if err := json.Unmarshal(res.Payload, &v); err != nil {
+		t.Fatalf("failed to unmarshal vehicle - %s", err.Error())
+	}
+
+	if v.Make != "Volvo" || v.Year != 2021 || v.Value != euros(30000) || v.Version != 1 {
+		t.Fatalf(`unexpected imported vehicle %s`, res.Payload)
+	}
+}
+
+func TestSmartContractImportAssetsResumes(t *testing.T) {
+	stub := newQueryStub()
+	var rows []string
+	for id := 0; id <= defaultMaxBatchSize; id++ {
+		rows = append(rows, fmt.Sprintf(`{"color":"blue","id":%d,"owner":"David","value":{"amount":100,"currency":"EUR"}}`, id))
+	}
+	stub.TransientMap = map[string][]byte{importKey: []byte(strings.Join(rows, "\n\n"))}
+
+	report := importAssets(stub, t, formatJSONL, "1")
+	if report.Imported != 1 || report.Chunks != 2 || report.NextChunk != -1 {
+		t.Fatalf(`unexpected import report %s`, marshal(report, t))
+	}
+
+	report = importAssets(stub, t, formatJSONL, "0")
+	if report.Imported != defaultMaxBatchSize || report.NextChunk != 1 || len(report.Skipped) != 0 {
+		t.Fatalf(`unexpected import report %s`, marshal(report, t))
+	}
+
+	// importing a chunk again only reports its records as duplicates
+	report = importAssets(stub, t, formatJSONL, "1")
+	if report.Imported != 0 || len(report.Duplicates) != 1 || report.Duplicates[0].Line != 2*defaultMaxBatchSize+1 {
+		t.Fatalf(`unexpected import report %s`, marshal(report, t))
+	}
+}
+
+func TestSmartContractImportAssetsInvalid(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "ImportAssets", formatCSV, "0"); DecodeError(res.Message).Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res.Message)
+	}
+
+	stub.TransientMap = map[string][]byte{importKey: []byte(`{"id":1}`)}
+	for _, args := range [][]string{
+		{"ImportAssets", "xml", "0"},
+		{"ImportAssets", formatJSONL, "1"},
+		{"ImportAssets", formatJSONL, "-1"},
+		{"ImportAssets", formatCSV, "0"},
+	} {
+		if res := stub.invoke(`2`, args...); DecodeError(res.Message).Code != CodeInvalid {
+			t.Fatalf(errExpect, CodeInvalid, res.Message)
+		}
+	}
+}
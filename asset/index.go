@@ -0,0 +1,203 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// object types of the composite keys used as secondary indexes, which unlike rich
+// queries are also available on peers running LevelDB
+const (
+	ownerIndexKey = `owner~type~id`
+	colorIndexKey = `color~type~id`
+)
+
+// index entries carry all information in their key, but an empty value would delete them
+var indexValue = []byte{0x00}
+
+// indexKeys returns the secondary index keys of a record
+func (s store[T, P]) indexKeys(ctx contractapi.TransactionContextInterface, r P) ([]string, error) {
+	var keys []string
+	for _, idx := range []struct{ name, attr string }{
+		{name: ownerIndexKey, attr: r.owner()},
+		{name: colorIndexKey, attr: r.color()},
+	} {
+		k, err := ctx.GetStub().CreateCompositeKey(idx.name, []string{idx.attr, s.kind, strconv.Itoa(r.id())})
+		if err != nil {
+			return nil, fmt.Errorf(`create %s index key failed for %s %d - %w`, idx.name, s.kind, r.id(), err)
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// reindex replaces the index entries of the previous version of a record with the ones
+// of its current version, where either of them is nil if the record does not exist
+func (s store[T, P]) reindex(ctx contractapi.TransactionContextInterface, prev, cur P) error {
+	var oldKeys, newKeys []string
+	var err error
+	if prev != nil {
+		if oldKeys, err = s.indexKeys(ctx, prev); err != nil {
+			return err
+		}
+	}
+
+	if cur != nil {
+		if newKeys, err = s.indexKeys(ctx, cur); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range oldKeys {
+		if contains(newKeys, k) {
+			continue
+		}
+
+		if err = ctx.GetStub().DelState(k); err != nil {
+			return fmt.Errorf(`deleting index entry of %s failed - %w`, s.kind, err)
+		}
+	}
+
+	for _, k := range newKeys {
+		if contains(oldKeys, k) {
+			continue
+		}
+
+		if err = ctx.GetStub().PutState(k, indexValue); err != nil {
+			return fmt.Errorf(`putting index entry of %s failed - %w`, s.kind, err)
+		}
+	}
+
+	return nil
+}
+
+// byIndex returns the records of the kind having the given attribute in an index
+func (s store[T, P]) byIndex(ctx contractapi.TransactionContextInterface, index, attr string, limit int) ([]*T, error) {
+	itr, err := ctx.GetStub().GetStateByPartialCompositeKey(index, []string{attr, s.kind})
+	if err != nil {
+		return nil, fmt.Errorf(`get %s index entries failed - %w`, index, err)
+	}
+	defer itr.Close()
+
+	rs := []*T{}
+	for itr.HasNext() {
+		if limit > 0 && len(rs) == limit {
+			return nil, errorf(CodeInvalid, `index %s returned more than %d %s records`, index, limit, s.kind)
+		}
+
+		res, err := itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf(`iterating next %s index entry failed - %w`, index, err)
+		}
+
+		_, attrs, err := ctx.GetStub().SplitCompositeKey(res.Key)
+		if err != nil {
+			return nil, fmt.Errorf(`splitting %s index key failed - %w`, index, err)
+		}
+
+		id, err := strconv.Atoi(attrs[2])
+		if err != nil {
+			return nil, fmt.Errorf(`invalid id in %s index key - %w`, index, err)
+		}
+
+		r, err := s.get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf(`resolving %s index entry failed - %w`, index, err)
+		}
+
+		rs = append(rs, r)
+	}
+
+	return rs, nil
+}
+
+// count returns the number of records of the kind having the given attribute in an index
+func (s store[T, P]) count(ctx contractapi.TransactionContextInterface, index, attr string) (int, error) {
+	itr, err := ctx.GetStub().GetStateByPartialCompositeKey(index, []string{attr, s.kind})
+	if err != nil {
+		return 0, fmt.Errorf(`get %s index entries failed - %w`, index, err)
+	}
+	defer itr.Close()
+
+	n := 0
+	for ; itr.HasNext(); n++ {
+		if _, err = itr.Next(); err != nil {
+			return 0, fmt.Errorf(`iterating next %s index entry failed - %w`, index, err)
+		}
+	}
+
+	return n, nil
+}
+
+// rebuildIndexes drops the index entries of the kind and regenerates them from the
+// records, returning the number of records indexed
+func (s store[T, P]) rebuildIndexes(ctx contractapi.TransactionContextInterface) (int, error) {
+	for _, index := range []string{ownerIndexKey, colorIndexKey} {
+		itr, err := ctx.GetStub().GetStateByPartialCompositeKey(index, []string{})
+		if err != nil {
+			return 0, fmt.Errorf(`get %s index entries failed - %w`, index, err)
+		}
+
+		var stale []string
+		for itr.HasNext() {
+			res, err := itr.Next()
+			if err != nil {
+				itr.Close()
+				return 0, fmt.Errorf(`iterating next %s index entry failed - %w`, index, err)
+			}
+
+			_, attrs, err := ctx.GetStub().SplitCompositeKey(res.Key)
+			if err != nil {
+				itr.Close()
+				return 0, fmt.Errorf(`splitting %s index key failed - %w`, index, err)
+			}
+
+			if attrs[1] == s.kind {
+				stale = append(stale, res.Key)
+			}
+		}
+		itr.Close()
+
+		for _, k := range stale {
+			if err = ctx.GetStub().DelState(k); err != nil {
+				return 0, fmt.Errorf(`deleting %s index entry failed - %w`, index, err)
+			}
+		}
+	}
+
+	rs, err := s.all(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, r := range rs {
+		if err = s.reindex(ctx, nil, r); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(rs), nil
+}
+
+// RebuildIndexes regenerates the owner and color index entries of an asset kind from
+// its records and returns the number of records indexed
+func (s *SmartContract) RebuildIndexes(ctx contractapi.TransactionContextInterface, kind string) (int, error) {
+	f, err := familyOf(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	return f.rebuildIndexes(ctx)
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
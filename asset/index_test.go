@@ -0,0 +1,89 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"strconv"
+	"testing"
+)
+
+func TestSmartContractGetAssetsByOwner(t *testing.T) {
+	stub := newMockStub()
+	testInitLedger(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{[]byte("TransferAsset"), []byte("2"), []byte(ownrDavid)}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if ats := getIndexed(stub, "GetAssetsByOwner", "Jane Doe", t); len(ats) != 0 {
+		t.Fatalf(`expected no assets of the previous owner, got %d`, len(ats))
+	}
+
+	ats := getIndexed(stub, "GetAssetsByOwner", ownrDavid, t)
+	if len(ats) != 1 || ats[0].ID != 2 {
+		t.Fatalf(`expected asset 2 to be owned by %s, got %v`, ownrDavid, ats)
+	}
+}
+
+func TestSmartContractGetAssetsByColor(t *testing.T) {
+	stub := newMockStub()
+	testInitLedger(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{[]byte("ChangeAssetColour"), []byte("1"), []byte("red")}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.MockInvoke(`2`, [][]byte{[]byte("DeleteAsset"), []byte("2")}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	ats := getIndexed(stub, "GetAssetsByColor", "red", t)
+	if len(ats) != 1 || ats[0].ID != 1 {
+		t.Fatalf(`expected only asset 1 to be red, got %v`, ats)
+	}
+}
+
+func TestSmartContractRebuildIndexes(t *testing.T) {
+	stub := newMockStub()
+	a := Asset{Color: clrBlue, DocType: kindAsset, ID: 7, Owner: ownrDavid, Value: euros(10)}
+
+	// a record written before indexes were maintained
+	stub.MockTransactionStart(`1`)
+	if err := stub.PutState(compositeKey(kindAsset, a.ID, t), marshal(a, t)); err != nil {
+		t.Fatalf("failed to put asset - %s", err.Error())
+	}
+	stub.MockTransactionEnd(`1`)
+
+	if ats := getIndexed(stub, "GetAssetsByOwner", ownrDavid, t); len(ats) != 0 {
+		t.Fatalf(`expected no indexed assets before rebuilding, got %d`, len(ats))
+	}
+
+	res := stub.MockInvoke(`2`, [][]byte{[]byte("RebuildIndexes"), []byte(kindAsset)})
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if string(res.Payload) != `1` {
+		t.Fatalf(errExpect, `1`, res.Payload)
+	}
+
+	ats := getIndexed(stub, "GetAssetsByOwner", ownrDavid, t)
+	if len(ats) != 1 || ats[0].ID != a.ID {
+		t.Fatalf(`expected asset %d to be indexed, got %v`, a.ID, ats)
+	}
+}
+
+func getIndexed(stub *shimtest.MockStub, fn, attr string, t *testing.T) []Asset {
+	res := stub.MockInvoke(strconv.Itoa(len(fn)), [][]byte{[]byte(fn), []byte(attr)})
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var ats []Asset
+	if err := json.Unmarshal(res.Payload, &ats); err != nil {
+		t.Fatalf("failed to unmarshal assets - %s", err.Error())
+	}
+
+	return ats
+}
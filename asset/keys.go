@@ -0,0 +1,128 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"sort"
+	"strconv"
+)
+
+// object types used as the composite key namespace of each asset kind
+const (
+	kindAsset   = `asset`
+	kindVehicle = `vehicle`
+	kindBook    = `book`
+	kindHouse   = `house`
+)
+
+// key returns the composite key of an asset so that equal ids of different kinds do not collide
+func key(ctx contractapi.TransactionContextInterface, kind string, id int) (string, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(kind, []string{strconv.Itoa(id)})
+	if err != nil {
+		return ``, fmt.Errorf(`create composite key failed for %s %d - %w`, kind, id, err)
+	}
+
+	return k, nil
+}
+
+func readState(ctx contractapi.TransactionContextInterface, kind string, id int) ([]byte, error) {
+	k, err := key(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.GetStub().GetState(k)
+}
+
+func writeState(ctx contractapi.TransactionContextInterface, kind string, id int, byts []byte) error {
+	k, err := key(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(k, byts)
+}
+
+func deleteState(ctx contractapi.TransactionContextInterface, kind string, id int) error {
+	k, err := key(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().DelState(k)
+}
+
+// MigrateLegacyKeys moves entries stored under flat id keys into the composite key namespace
+// of the given kind. Flat keys carry no kind, hence the caller states which kind they belong
+// to and may restrict the migration to a set of ids (an empty list migrates every flat key).
+// It returns the number of migrated entries.
+func (s *SmartContract) MigrateLegacyKeys(ctx contractapi.TransactionContextInterface, kind string, ids []int) (int, error) {
+	f, err := familyOf(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	legacy := make(map[int][]byte)
+	if len(ids) == 0 {
+		itr, err := ctx.GetStub().GetStateByRange(``, ``)
+		if err != nil {
+			return 0, fmt.Errorf(`get state by range failed - %w`, err)
+		}
+		defer itr.Close()
+
+		for itr.HasNext() {
+			res, err := itr.Next()
+			if err != nil {
+				return 0, fmt.Errorf(`iterating next legacy key failed - %w`, err)
+			}
+
+			// keys which are not plain integers were never written by the flat key scheme
+			id, err := strconv.Atoi(res.Key)
+			if err != nil {
+				continue
+			}
+			legacy[id] = res.Value
+		}
+	}
+
+	for _, id := range ids {
+		byts, err := ctx.GetStub().GetState(strconv.Itoa(id))
+		if err != nil {
+			return 0, fmt.Errorf(`get legacy state failed for id %d - %w`, id, err)
+		}
+
+		if byts == nil {
+			return 0, errorf(CodeNotFound, `legacy entry with id %d does not exist`, id)
+		}
+		legacy[id] = byts
+	}
+
+	// ranging over a map is random, hence ids are sorted to keep endorsements deterministic
+	migrated := make([]int, 0, len(legacy))
+	for id := range legacy {
+		migrated = append(migrated, id)
+	}
+	sort.Ints(migrated)
+
+	for _, id := range migrated {
+		exists, err := f.exists(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+
+		if exists {
+			return 0, errorf(CodeAlreadyExists, `%s with id %d already exists under its composite key`, kind, id)
+		}
+
+		// records are rewritten through the store to tag and index them like any other record
+		if err = f.putEncoded(ctx, id, legacy[id]); err != nil {
+			return 0, fmt.Errorf(`put state failed for %s %d - %w`, kind, id, err)
+		}
+
+		if err = ctx.GetStub().DelState(strconv.Itoa(id)); err != nil {
+			return 0, fmt.Errorf(`deleting legacy key failed for id %d - %w`, id, err)
+		}
+	}
+
+	return len(migrated), nil
+}
@@ -0,0 +1,77 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"strconv"
+	"testing"
+)
+
+func TestSmartContractKindsDoNotCollide(t *testing.T) {
+	stub := newMockStub()
+	testCreate(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateVehicle"), []byte(clrBlue), []byte(strconv.Itoa(testAsset.ID)), []byte(ownrDavid), []byte("10"),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	in := marshalAsset()
+	out := getState(stub, testAsset.ID, t)
+	if !sameRecords(in, out, t) {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractMigrateLegacyKeys(t *testing.T) {
+	stub := newMockStub()
+	legacy := Asset{Color: clrBlue, ID: 104, Owner: ownrDavid, Value: euros(700)}
+
+	stub.MockTransactionStart(`1`)
+	if err := stub.PutState(strconv.Itoa(legacy.ID), marshal(legacy, t)); err != nil {
+		t.Fatalf("failed to put legacy state - %s", err.Error())
+	}
+	stub.MockTransactionEnd(`1`)
+
+	res := stub.MockInvoke(`2`, [][]byte{[]byte("MigrateLegacyKeys"), []byte(kindVehicle), []byte("[]")})
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if string(res.Payload) != `1` {
+		t.Fatalf(errExpect, `1`, res.Payload)
+	}
+
+	// migrated records take the shape of the kind and are tagged with it
+	in := marshal(Vehicle{Color: legacy.Color, DocType: kindVehicle, ID: legacy.ID, Owner: legacy.Owner, Value: legacy.Value}, t)
+	out := getVehicleState(stub, legacy.ID, t)
+	if !sameRecords(in, out, t) {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	flat, err := stub.GetState(strconv.Itoa(legacy.ID))
+	if err != nil {
+		t.Fatalf("failed to retrieve legacy state - %s", err.Error())
+	}
+
+	if flat != nil {
+		t.Fatalf(`legacy key should be removed after migration (%s)`, string(flat))
+	}
+
+	// migrated records of legacy owners are endorsed by the org of the admin migrating them
+	k, err := stub.CreateCompositeKey(kindVehicle, []string{strconv.Itoa(legacy.ID)})
+	if err != nil {
+		t.Fatalf("failed to create key - %s", err.Error())
+	}
+
+	if ep, err := stub.GetStateValidationParameter(k); err != nil || ep == nil {
+		t.Fatalf(`migrated record should have an endorsement policy (%v)`, err)
+	}
+}
+
+func TestSmartContractMigrateLegacyKeysUnknownKind(t *testing.T) {
+	stub := newMockStub()
+	if res := stub.MockInvoke(`1`, [][]byte{[]byte("MigrateLegacyKeys"), []byte("boat"), []byte("[]")}); res.Status == shim.OK {
+		t.Fatalf(`migration into an unknown kind should fail`)
+	}
+}
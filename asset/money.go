@@ -0,0 +1,161 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of values given without one, including the plain
+// integer values recorded before values carried a currency
+const DefaultCurrency = `EUR`
+
+var (
+	amountPattern = regexp.MustCompile(`^(-?)([0-9]+)(?:\.([0-9]+))?$`)
+
+	// currencies are the ISO-4217 currency codes in use along with the decimals of their minor
+	// unit, leaving out precious metals and other codes without one
+	currencies = map[string]int{
+		`AED`: 2, `AFN`: 2, `ALL`: 2, `AMD`: 2, `ANG`: 2, `AOA`: 2, `ARS`: 2, `AUD`: 2, `AWG`: 2, `AZN`: 2,
+		`BAM`: 2, `BBD`: 2, `BDT`: 2, `BGN`: 2, `BHD`: 3, `BIF`: 0, `BMD`: 2, `BND`: 2, `BOB`: 2, `BOV`: 2,
+		`BRL`: 2, `BSD`: 2, `BTN`: 2, `BWP`: 2, `BYN`: 2, `BZD`: 2, `CAD`: 2, `CDF`: 2, `CHE`: 2, `CHF`: 2,
+		`CHW`: 2, `CLF`: 4, `CLP`: 0, `CNY`: 2, `COP`: 2, `COU`: 2, `CRC`: 2, `CUC`: 2, `CUP`: 2, `CVE`: 2,
+		`CZK`: 2, `DJF`: 0, `DKK`: 2, `DOP`: 2, `DZD`: 2, `EGP`: 2, `ERN`: 2, `ETB`: 2, `EUR`: 2, `FJD`: 2,
+		`FKP`: 2, `GBP`: 2, `GEL`: 2, `GHS`: 2, `GIP`: 2, `GMD`: 2, `GNF`: 0, `GTQ`: 2, `GYD`: 2, `HKD`: 2,
+		`HNL`: 2, `HTG`: 2, `HUF`: 2, `IDR`: 2, `ILS`: 2, `INR`: 2, `IQD`: 3, `IRR`: 2, `ISK`: 0, `JMD`: 2,
+		`JOD`: 3, `JPY`: 0, `KES`: 2, `KGS`: 2, `KHR`: 2, `KMF`: 0, `KPW`: 2, `KRW`: 0, `KWD`: 3, `KYD`: 2,
+		`KZT`: 2, `LAK`: 2, `LBP`: 2, `LKR`: 2, `LRD`: 2, `LSL`: 2, `LYD`: 3, `MAD`: 2, `MDL`: 2, `MGA`: 2,
+		`MKD`: 2, `MMK`: 2, `MNT`: 2, `MOP`: 2, `MRU`: 2, `MUR`: 2, `MVR`: 2, `MWK`: 2, `MXN`: 2, `MXV`: 2,
+		`MYR`: 2, `MZN`: 2, `NAD`: 2, `NGN`: 2, `NIO`: 2, `NOK`: 2, `NPR`: 2, `NZD`: 2, `OMR`: 3, `PAB`: 2,
+		`PEN`: 2, `PGK`: 2, `PHP`: 2, `PKR`: 2, `PLN`: 2, `PYG`: 0, `QAR`: 2, `RON`: 2, `RSD`: 2, `RUB`: 2,
+		`RWF`: 0, `SAR`: 2, `SBD`: 2, `SCR`: 2, `SDG`: 2, `SEK`: 2, `SGD`: 2, `SHP`: 2, `SLE`: 2, `SLL`: 2,
+		`SOS`: 2, `SRD`: 2, `SSP`: 2, `STN`: 2, `SVC`: 2, `SYP`: 2, `SZL`: 2, `THB`: 2, `TJS`: 2, `TMT`: 2,
+		`TND`: 3, `TOP`: 2, `TRY`: 2, `TTD`: 2, `TWD`: 2, `TZS`: 2, `UAH`: 2, `UGX`: 0, `USD`: 2, `USN`: 2,
+		`UYI`: 0, `UYU`: 2, `UYW`: 4, `UZS`: 2, `VED`: 2, `VES`: 2, `VND`: 0, `VUV`: 0, `WST`: 2, `XAF`: 0,
+		`XCD`: 2, `XCG`: 2, `XOF`: 0, `XPF`: 0, `YER`: 2, `ZAR`: 2, `ZMW`: 2, `ZWG`: 2, `ZWL`: 2,
+	}
+)
+
+// Money is a fixed point amount of an ISO-4217 currency. Amount counts the minor unit of
+// the currency, so that its JSON encoding only holds integers and is identical on every peer.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// minorUnits returns the number of decimals of a currency
+func minorUnits(currency string) (int, error) {
+	units, ok := currencies[currency]
+	if !ok {
+		return 0, fmt.Errorf(`currency %q is not an ISO-4217 code`, currency)
+	}
+
+	return units, nil
+}
+
+// parseMoney parses a decimal amount optionally followed by a currency, such as 12.50 EUR,
+// where the amount is in the default currency if none is given
+func parseMoney(s string) (Money, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Money{}, fmt.Errorf(`money %q must be an amount optionally followed by a currency`, s)
+	}
+
+	currency := DefaultCurrency
+	if len(fields) == 2 {
+		currency = fields[1]
+	}
+
+	units, err := minorUnits(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	m := amountPattern.FindStringSubmatch(fields[0])
+	if m == nil {
+		return Money{}, fmt.Errorf(`amount %q is not a decimal number`, fields[0])
+	}
+
+	if len(m[3]) > units {
+		return Money{}, fmt.Errorf(`amount %s has more than %d decimals of %s`, fields[0], units, currency)
+	}
+
+	// the digits of the minor unit are parsed at once, which also rejects amounts overflowing them
+	amount, err := strconv.ParseInt(m[1]+m[2]+m[3]+strings.Repeat(`0`, units-len(m[3])), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf(`amount %s is out of range - %w`, fields[0], err)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// String formats the amount in the major unit followed by the currency
+func (m Money) String() string {
+	units, err := minorUnits(m.Currency)
+	if err != nil || units == 0 {
+		return fmt.Sprintf(`%d %s`, m.Amount, m.Currency)
+	}
+
+	sign, amount := ``, uint64(m.Amount)
+	if m.Amount < 0 {
+		sign, amount = `-`, uint64(-(m.Amount+1))+1
+	}
+
+	scale := uint64(math.Pow10(units))
+	return fmt.Sprintf(`%s%d.%0*d %s`, sign, amount/scale, units, amount%scale, m.Currency)
+}
+
+// Add returns the sum of two amounts of the same currency
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf(`can not add %s to %s`, o.Currency, m.Currency)
+	}
+
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) || (o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, fmt.Errorf(`adding %s to %s overflows`, o, m)
+	}
+
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts of the same currency
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf(`can not subtract %s from %s`, o.Currency, m.Currency)
+	}
+
+	if (o.Amount < 0 && m.Amount > math.MaxInt64+o.Amount) || (o.Amount > 0 && m.Amount < math.MinInt64+o.Amount) {
+		return Money{}, fmt.Errorf(`subtracting %s from %s overflows`, o, m)
+	}
+
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// UnmarshalJSON also accepts the plain integers recorded before values carried a
+// currency, which are read as whole amounts of the default currency
+func (m *Money) UnmarshalJSON(byts []byte) error {
+	var legacy int64
+	if err := json.Unmarshal(byts, &legacy); err == nil {
+		units, _ := minorUnits(DefaultCurrency)
+		scale := int64(math.Pow10(units))
+		if legacy > math.MaxInt64/scale || legacy < math.MinInt64/scale {
+			return fmt.Errorf(`legacy value %d is out of range`, legacy)
+		}
+
+		*m = Money{Amount: legacy * scale, Currency: DefaultCurrency}
+		return nil
+	}
+
+	// the alias drops this method, which would otherwise recurse
+	type money Money
+	var v money
+	if err := json.Unmarshal(byts, &v); err != nil {
+		return err
+	}
+
+	*m = Money(v)
+	return nil
+}
@@ -0,0 +1,129 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// euros returns a whole amount of the default currency
+func euros(n int64) Money {
+	return Money{Amount: n * 100, Currency: DefaultCurrency}
+}
+
+func TestParseMoney(t *testing.T) {
+	for in, out := range map[string]Money{
+		"766":        euros(766),
+		"12.5":       {Amount: 1250, Currency: `EUR`},
+		"12.34 USD":  {Amount: 1234, Currency: `USD`},
+		"1500 JPY":   {Amount: 1500, Currency: `JPY`},
+		"0.125 KWD":  {Amount: 125, Currency: `KWD`},
+		"-3.07 GBP ": {Amount: -307, Currency: `GBP`},
+		"1.2345 CLF": {Amount: 12345, Currency: `CLF`},
+	} {
+		m, err := parseMoney(in)
+		if err != nil {
+			t.Fatalf("failed to parse %q - %s", in, err.Error())
+		}
+
+		if m != out {
+			t.Fatalf(errExpect, out, m)
+		}
+	}
+
+	for _, in := range []string{``, `1,5`, `12.345`, `1.5 JPY`, `10 eur`, `10 EURO`, `10 ABC`, `10 XAU`, `1 EUR x`, `92233720368547758.08`} {
+		if _, err := parseMoney(in); err == nil {
+			t.Fatalf(`parsing %q should fail`, in)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	for in, out := range map[Money]string{
+		euros(766):                               `766.00 EUR`,
+		{Amount: -5, Currency: `USD`}:            `-0.05 USD`,
+		{Amount: 1500, Currency: `JPY`}:          `1500 JPY`,
+		{Amount: math.MinInt64, Currency: `EUR`}: `-92233720368547758.08 EUR`,
+		{Amount: 125, Currency: `KWD`}:           `0.125 KWD`,
+	} {
+		if in.String() != out {
+			t.Fatalf(errExpect, out, in.String())
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	sum, err := euros(2).Add(Money{Amount: 50, Currency: DefaultCurrency})
+	if err != nil || sum.Amount != 250 {
+		t.Fatalf(`2.00 EUR + 0.50 EUR should be 2.50 EUR (%s, %v)`, sum, err)
+	}
+
+	diff, err := euros(2).Sub(euros(3))
+	if err != nil || diff != euros(-1) {
+		t.Fatalf(`2.00 EUR - 3.00 EUR should be -1.00 EUR (%s, %v)`, diff, err)
+	}
+
+	if _, err = euros(1).Add(Money{Amount: 1, Currency: `USD`}); err == nil {
+		t.Fatalf(`adding different currencies should fail`)
+	}
+
+	if _, err = (Money{Amount: math.MaxInt64, Currency: `EUR`}).Add(Money{Amount: 1, Currency: `EUR`}); err == nil {
+		t.Fatalf(`adding beyond the range of the amount should fail`)
+	}
+
+	if _, err = (Money{Amount: math.MinInt64, Currency: `EUR`}).Sub(Money{Amount: 1, Currency: `EUR`}); err == nil {
+		t.Fatalf(`subtracting beyond the range of the amount should fail`)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	byts, err := json.Marshal(Money{Amount: 1234, Currency: `USD`})
+	if err != nil {
+		t.Fatalf("failed to marshal money - %s", err.Error())
+	}
+
+	if in, out := `{"amount":1234,"currency":"USD"}`, string(byts); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	var m Money
+	if err = json.Unmarshal(byts, &m); err != nil || m != (Money{Amount: 1234, Currency: `USD`}) {
+		t.Fatalf(`money should read back as 12.34 USD (%s, %v)`, m, err)
+	}
+
+	// values recorded as plain integers read back in the default currency
+	var a Asset
+	if err = json.Unmarshal([]byte(`{"color":"blue","docType":"asset","id":1,"owner":"Bill","value":450}`), &a); err != nil {
+		t.Fatalf("failed to unmarshal legacy asset - %s", err.Error())
+	}
+
+	if a.Value != euros(450) {
+		t.Fatalf(errExpect, euros(450), a.Value)
+	}
+}
+
+func TestSmartContractAssetValueCurrency(t *testing.T) {
+	stub := newMockStub()
+	testCreate(stub, t)
+
+	if res := stub.MockInvoke(`9`, toArgs([]string{"ChangeAssetValue", strconv.Itoa(testAsset.ID), "12.34 USD"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(getState(stub, testAsset.ID, t), &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if in := (Money{Amount: 1234, Currency: `USD`}); a.Value != in {
+		t.Fatalf(errExpect, in, a.Value)
+	}
+
+	for _, val := range []string{"-1", "1.234 USD", "ten"} {
+		if res := stub.MockInvoke(`10`, toArgs([]string{"ChangeAssetValue", strconv.Itoa(testAsset.ID), val})); res.Status == shim.OK {
+			t.Fatalf(`changing the value to %q should fail`, val)
+		}
+	}
+}
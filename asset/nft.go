@@ -0,0 +1,277 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// EventApprovalForAll is the ERC-721 event reporting operators of all tokens of an owner
+const EventApprovalForAll = `ApprovalForAll`
+
+// object types of the approvals of the NFT contract
+const (
+	nftApprovalKey = `nft~approval~id`
+	nftOperatorKey = `nft~operator~owner~operator`
+)
+
+// NFTContract exposes the assets as ERC-721 style non-fungible tokens, whose token
+// ids are the asset ids and whose owners are the asset owners
+type NFTContract struct {
+	contractapi.Contract
+	// BaseURI is prefixed to the token id to form the metadata URI of a token
+	BaseURI string
+}
+
+// NFTTransferEvent is the payload of EventTransfer emitted by the NFT contract, which also
+// carries the change of the asset, as a transaction emits a single event and hence no
+// EventAssetTransferred is emitted for transfers of tokens
+type NFTTransferEvent struct {
+	Asset   Event  `json:"asset"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	TokenID int    `json:"tokenId"`
+}
+
+// NFTApprovalEvent is the payload of EventApproval emitted by the NFT contract
+type NFTApprovalEvent struct {
+	Approved string `json:"approved"`
+	Owner    string `json:"owner"`
+	TokenID  int    `json:"tokenId"`
+}
+
+// ApprovalForAllEvent is the payload of EventApprovalForAll
+type ApprovalForAllEvent struct {
+	Approved bool   `json:"approved"`
+	Operator string `json:"operator"`
+	Owner    string `json:"owner"`
+}
+
+// approval grants a single token, and is revoked once the owner who granted it hands it over
+type approval struct {
+	Approved string `json:"approved"`
+	Owner    string `json:"owner"`
+}
+
+// revokeApproval drops the approval of the token of an asset, as the approval would apply
+// again if the asset ever returned to the owner who granted it
+func (f family[T, P]) revokeApproval(ctx contractapi.TransactionContextInterface, id int) error {
+	if f.store.kind != kindAsset {
+		return nil
+	}
+
+	k, err := ctx.GetStub().CreateCompositeKey(nftApprovalKey, []string{strconv.Itoa(id)})
+	if err != nil {
+		return fmt.Errorf(`create approval key failed for token %d - %w`, id, err)
+	}
+
+	if err = ctx.GetStub().DelState(k); err != nil {
+		return fmt.Errorf(`deleting approval failed for token %d - %w`, id, err)
+	}
+
+	return nil
+}
+
+// GetTransactionContextHandler buffers the events of the asset store, which are never
+// emitted as the NFT contract reports its changes with the standard ERC-721 events, where
+// NFTTransferEvent carries the buffered change of the asset
+func (n *NFTContract) GetTransactionContextHandler() contractapi.SettableTransactionContextInterface {
+	return new(txContext)
+}
+
+// OwnerOf returns the owner of the asset of the token id
+func (n *NFTContract) OwnerOf(ctx contractapi.TransactionContextInterface, tokenID int) (string, error) {
+	a, err := assetFamily.get(ctx, tokenID)
+	if err != nil {
+		return ``, err
+	}
+
+	return a.Owner, nil
+}
+
+// BalanceOf returns the number of assets of an owner
+func (n *NFTContract) BalanceOf(ctx contractapi.TransactionContextInterface, owner string) (int, error) {
+	return assetFamily.store.count(ctx, ownerIndexKey, owner)
+}
+
+// TransferFrom transfers the asset of the token id from its owner, given that the
+// invoker is the owner, approved for the token or an operator of the owner
+func (n *NFTContract) TransferFrom(ctx contractapi.TransactionContextInterface, from string, to string, tokenID int) error {
+	a, err := assetFamily.get(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+
+	if a.Owner != from {
+		return errorf(CodeInvalid, `token %d is not owned by %s`, tokenID, from)
+	}
+
+	if to == `` {
+		return errorf(CodeInvalid, `recipient of token %d is required`, tokenID)
+	}
+
+	if err = n.authorize(ctx, a.Owner, tokenID, true); err != nil {
+		return err
+	}
+
+	if err = assetFamily.transferable(ctx, tokenID); err != nil {
+		return err
+	}
+
+	if err = assetFamily.change(ctx, tokenID, EventAssetTransferred, assetFamily.handOver(ctx, to)); err != nil {
+		return err
+	}
+
+	e := NFTTransferEvent{From: from, To: to, TokenID: tokenID}
+	if tc, ok := ctx.(*txContext); ok && len(tc.events) > 0 {
+		e.Asset = tc.events[len(tc.events)-1]
+	}
+
+	return setEvent(ctx, EventTransfer, e)
+}
+
+// Approve lets the approved client transfer the token, where an empty approved revokes it
+func (n *NFTContract) Approve(ctx contractapi.TransactionContextInterface, approved string, tokenID int) error {
+	a, err := assetFamily.get(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+
+	if err = n.authorize(ctx, a.Owner, tokenID, false); err != nil {
+		return err
+	}
+
+	k, err := ctx.GetStub().CreateCompositeKey(nftApprovalKey, []string{strconv.Itoa(tokenID)})
+	if err != nil {
+		return fmt.Errorf(`create approval key failed for token %d - %w`, tokenID, err)
+	}
+
+	byts, err := json.Marshal(approval{Approved: approved, Owner: a.Owner})
+	if err != nil {
+		return fmt.Errorf(`marshal approval failed for token %d - %w`, tokenID, err)
+	}
+
+	if err = ctx.GetStub().PutState(k, byts); err != nil {
+		return fmt.Errorf(`put approval failed for token %d - %w`, tokenID, err)
+	}
+
+	return setEvent(ctx, EventApproval, NFTApprovalEvent{Approved: approved, Owner: a.Owner, TokenID: tokenID})
+}
+
+// GetApproved returns the client approved for the token, if any
+func (n *NFTContract) GetApproved(ctx contractapi.TransactionContextInterface, tokenID int) (string, error) {
+	a, err := assetFamily.get(ctx, tokenID)
+	if err != nil {
+		return ``, err
+	}
+
+	k, err := ctx.GetStub().CreateCompositeKey(nftApprovalKey, []string{strconv.Itoa(tokenID)})
+	if err != nil {
+		return ``, fmt.Errorf(`create approval key failed for token %d - %w`, tokenID, err)
+	}
+
+	byts, err := ctx.GetStub().GetState(k)
+	if err != nil {
+		return ``, fmt.Errorf(`get approval failed for token %d - %w`, tokenID, err)
+	}
+
+	if byts == nil {
+		return ``, nil
+	}
+
+	var ap approval
+	if err = json.Unmarshal(byts, &ap); err != nil {
+		return ``, fmt.Errorf(`unmarshal approval failed for token %d - %w`, tokenID, err)
+	}
+
+	if ap.Owner != a.Owner {
+		return ``, nil
+	}
+
+	return ap.Approved, nil
+}
+
+// SetApprovalForAll lets the operator transfer and approve all tokens of the invoker
+func (n *NFTContract) SetApprovalForAll(ctx contractapi.TransactionContextInterface, operator string, approved bool) error {
+	owner, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	k, err := ctx.GetStub().CreateCompositeKey(nftOperatorKey, []string{owner, operator})
+	if err != nil {
+		return fmt.Errorf(`create operator key failed - %w`, err)
+	}
+
+	if approved {
+		err = ctx.GetStub().PutState(k, []byte(strconv.FormatBool(approved)))
+	} else {
+		err = ctx.GetStub().DelState(k)
+	}
+
+	if err != nil {
+		return fmt.Errorf(`setting operator %s of %s failed - %w`, operator, owner, err)
+	}
+
+	return setEvent(ctx, EventApprovalForAll, ApprovalForAllEvent{Approved: approved, Operator: operator, Owner: owner})
+}
+
+// IsApprovedForAll reports whether the operator may transfer all tokens of the owner
+func (n *NFTContract) IsApprovedForAll(ctx contractapi.TransactionContextInterface, owner string, operator string) (bool, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(nftOperatorKey, []string{owner, operator})
+	if err != nil {
+		return false, fmt.Errorf(`create operator key failed - %w`, err)
+	}
+
+	byts, err := ctx.GetStub().GetState(k)
+	if err != nil {
+		return false, fmt.Errorf(`get operator %s of %s failed - %w`, operator, owner, err)
+	}
+
+	return byts != nil, nil
+}
+
+// TokenURI returns the URI of the metadata of the token
+func (n *NFTContract) TokenURI(ctx contractapi.TransactionContextInterface, tokenID int) (string, error) {
+	if _, err := assetFamily.get(ctx, tokenID); err != nil {
+		return ``, err
+	}
+
+	if n.BaseURI == `` {
+		return ``, errorf(CodeInternal, `base uri of the tokens is not configured`)
+	}
+
+	return n.BaseURI + strconv.Itoa(tokenID), nil
+}
+
+// authorize fails unless the invoker is the owner of the token or its operator, or
+// approved for the token if approvals are accepted
+func (n *NFTContract) authorize(ctx contractapi.TransactionContextInterface, owner string, tokenID int, approvals bool) error {
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller == owner {
+		return nil
+	}
+
+	operator, err := n.IsApprovedForAll(ctx, owner, caller)
+	if err != nil || operator {
+		return err
+	}
+
+	if approvals {
+		approved, err := n.GetApproved(ctx, tokenID)
+		if err != nil {
+			return err
+		}
+
+		if approved != `` && approved == caller {
+			return nil
+		}
+	}
+
+	return errorf(CodeForbidden, `%s is not allowed to manage token %d`, caller, tokenID)
+}
@@ -0,0 +1,147 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"testing"
+)
+
+func newNFTStub(t *testing.T) (*shimtest.MockStub, string, string) {
+	cc, err := contractapi.NewChaincode(&SmartContract{AdminMSPs: []string{testMSP}}, &NFTContract{BaseURI: "https://assets.example/"})
+	if err != nil {
+		t.Fatalf("error creating chaincode - %s", err.Error())
+	}
+
+	stub := shimtest.NewMockStub("nftStub", cc)
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub, t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub, t)
+
+	if res := stub.MockInvoke(`1`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+	<-stub.ChaincodeEventsChannel
+
+	return stub, alice, bob
+}
+
+func invokeNFT(stub *shimtest.MockStub, creator []byte, args ...string) ([]byte, string, bool) {
+	stub.Creator = creator
+	args[0] = "NFTContract:" + args[0]
+	res := stub.MockInvoke(args[0], toArgs(args))
+
+	return res.Payload, res.Message, res.Status == shim.OK
+}
+
+func testNFTQuery(stub *shimtest.MockStub, in string, t *testing.T, args ...string) {
+	out, msg, ok := invokeNFT(stub, aliceIdentity, args...)
+	if !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+
+	if string(out) != in {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestNFTContractOwnerOf(t *testing.T) {
+	stub, alice, _ := newNFTStub(t)
+
+	testNFTQuery(stub, alice, t, "OwnerOf", "5")
+	testNFTQuery(stub, `1`, t, "BalanceOf", alice)
+	testNFTQuery(stub, `https://assets.example/5`, t, "TokenURI", "5")
+}
+
+func TestNFTContractTransferFrom(t *testing.T) {
+	stub, alice, bob := newNFTStub(t)
+
+	if _, _, ok := invokeNFT(stub, bobIdentity, "TransferFrom", alice, bob, "5"); ok {
+		t.Fatalf(`transferring a token without approval should fail`)
+	}
+
+	if _, msg, ok := invokeNFT(stub, aliceIdentity, "Approve", bob, "5"); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+	<-stub.ChaincodeEventsChannel
+
+	if _, msg, ok := invokeNFT(stub, bobIdentity, "TransferFrom", alice, bob, "5"); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+
+	e := <-stub.ChaincodeEventsChannel
+	if e.EventName != EventTransfer {
+		t.Fatalf(errExpect, EventTransfer, e.EventName)
+	}
+
+	// the ERC-721 event carries the asset event, which is not emitted on its own
+	var transfer NFTTransferEvent
+	if err := json.Unmarshal(e.Payload, &transfer); err != nil {
+		t.Fatalf("failed to unmarshal transfer event - %s", err.Error())
+	}
+
+	if transfer.Asset.Operation != EventAssetTransferred || transfer.Asset.NewOwner != bob || transfer.Asset.ID != 5 {
+		t.Fatalf(`unexpected transfer event %s`, e.Payload)
+	}
+
+	// the NFT and the asset share the same owner, while the approval lapsed
+	testNFTQuery(stub, bob, t, "OwnerOf", "5")
+	testNFTQuery(stub, ``, t, "GetApproved", "5")
+
+	var a Asset
+	if err := json.Unmarshal(getState(stub, 5, t), &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if a.Owner != bob {
+		t.Fatalf(errExpect, bob, a.Owner)
+	}
+}
+
+func TestNFTContractApprovalRevokedOnTransfer(t *testing.T) {
+	stub, alice, bob := newNFTStub(t)
+
+	carol := newIdentity(testMSP, "carol", "")
+	stub.Creator = carol
+	carolOwner := ownerOf(stub, t)
+
+	if _, msg, ok := invokeNFT(stub, aliceIdentity, "Approve", carolOwner, "5"); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+	<-stub.ChaincodeEventsChannel
+
+	// the asset travels from alice to bob and back, outside of the NFT contract
+	stub.Creator = aliceIdentity
+	if res := stub.MockInvoke(`2`, toArgs([]string{"TransferAsset", "5", bob})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+	<-stub.ChaincodeEventsChannel
+
+	stub.Creator = bobIdentity
+	if res := stub.MockInvoke(`3`, toArgs([]string{"TransferAsset", "5", alice})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+	<-stub.ChaincodeEventsChannel
+
+	testNFTQuery(stub, ``, t, "GetApproved", "5")
+	if _, _, ok := invokeNFT(stub, carol, "TransferFrom", alice, carolOwner, "5"); ok {
+		t.Fatalf(`an approval should not outlive the transfer of the token`)
+	}
+}
+
+func TestNFTContractSetApprovalForAll(t *testing.T) {
+	stub, alice, bob := newNFTStub(t)
+
+	if _, msg, ok := invokeNFT(stub, aliceIdentity, "SetApprovalForAll", bob, "true"); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+	testNFTQuery(stub, `true`, t, "IsApprovedForAll", alice, bob)
+
+	if _, msg, ok := invokeNFT(stub, bobIdentity, "TransferFrom", alice, bob, "5"); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+	testNFTQuery(stub, `0`, t, "BalanceOf", alice)
+	testNFTQuery(stub, `1`, t, "BalanceOf", bob)
+}
@@ -0,0 +1,128 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strings"
+)
+
+// the certificate attribute granting administrative rights over the records of the owners
+// of the org, or of every owner for the admin orgs of the contract
+const (
+	roleAttr  = `role`
+	roleAdmin = `admin`
+)
+
+// invoker returns the identity of the client submitting the transaction, which is
+// recorded as owner in the form <msp id>::<certificate id>
+func invoker(ctx contractapi.TransactionContextInterface) (string, error) {
+	ci := ctx.GetClientIdentity()
+	if ci == nil {
+		return ``, fmt.Errorf(`client identity of the transaction is not available`)
+	}
+
+	mspID, err := ci.GetMSPID()
+	if err != nil {
+		return ``, fmt.Errorf(`get msp id of the client failed - %w`, err)
+	}
+
+	certID, err := ci.GetID()
+	if err != nil {
+		return ``, fmt.Errorf(`get certificate id of the client failed - %w`, err)
+	}
+
+	return mspID + `::` + certID, nil
+}
+
+// ownerMSP returns the MSP ID of an owner recorded as <msp id>::<certificate id>, or
+// an empty string for owners which are not recorded as client identities
+func ownerMSP(owner string) string {
+	parts := strings.SplitN(owner, `::`, 2)
+	if len(parts) < 2 {
+		return ``
+	}
+
+	return parts[0]
+}
+
+// isAdmin reports whether the invoker administers the records of the owner. Admins of the
+// admin orgs administer every record, while the admins of any other org only administer the
+// records owned by members of their org, as every org can issue certificates with the role.
+func isAdmin(ctx contractapi.TransactionContextInterface, owner string) (bool, error) {
+	ci := ctx.GetClientIdentity()
+	if ci == nil {
+		return false, fmt.Errorf(`client identity of the transaction is not available`)
+	}
+
+	role, found, err := ci.GetAttributeValue(roleAttr)
+	if err != nil {
+		return false, fmt.Errorf(`get %s attribute of the client failed - %w`, roleAttr, err)
+	}
+
+	if !found || role != roleAdmin {
+		return false, nil
+	}
+
+	mspID, err := ci.GetMSPID()
+	if err != nil {
+		return false, fmt.Errorf(`get msp id of the client failed - %w`, err)
+	}
+
+	return mspID == ownerMSP(owner) || contains(adminMSPs(ctx), mspID), nil
+}
+
+// adminMSPs returns the admin orgs the contract of the transaction is configured with
+func adminMSPs(ctx contractapi.TransactionContextInterface) []string {
+	if tc, ok := ctx.(*txContext); ok {
+		return tc.adminMSPs
+	}
+
+	return nil
+}
+
+// ownerOnCreate returns the owner of a new record, which is the invoker unless an
+// admin of the new owner assigns the record to them
+func ownerOnCreate(ctx contractapi.TransactionContextInterface, owner string) (string, error) {
+	caller, err := invoker(ctx)
+	if err != nil {
+		return ``, err
+	}
+
+	if owner == `` || owner == caller {
+		return caller, nil
+	}
+
+	admin, err := isAdmin(ctx, owner)
+	if err != nil {
+		return ``, err
+	}
+
+	if !admin {
+		return ``, errorf(CodeForbidden, `only an admin of the owner can create records owned by %s`, owner)
+	}
+
+	return owner, nil
+}
+
+// authorizeOwner fails unless the invoker is the given owner or one of their admins
+func authorizeOwner(ctx contractapi.TransactionContextInterface, owner string) error {
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller == owner {
+		return nil
+	}
+
+	admin, err := isAdmin(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if !admin {
+		return errorf(CodeForbidden, `%s is neither the owner nor an admin of the owner`, caller)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package asset
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/tryfix/log"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testMSP = "Org1MSP"
+
+var (
+	// fabric CA encodes the attributes of an identity under this certificate extension
+	attrsOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+	adminIdentity = newIdentity(testMSP, "admin", roleAdmin)
+	aliceIdentity = newIdentity(testMSP, "alice", "")
+	bobIdentity   = newIdentity(testMSP, "bob", "")
+)
+
+// newIdentity returns a serialized identity with a self signed certificate carrying the given role
+func newIdentity(mspID, cn, role string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatal("failed to generate key - ", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	if role != "" {
+		attrs, err := json.Marshal(map[string]interface{}{"attrs": map[string]string{roleAttr: role}})
+		if err != nil {
+			log.Fatal("failed to marshal attributes - ", err)
+		}
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: attrsOID, Value: attrs}}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		log.Fatal("failed to create certificate - ", err)
+	}
+
+	byts, err := proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   mspID,
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+	if err != nil {
+		log.Fatal("failed to marshal identity - ", err)
+	}
+
+	return byts
+}
+
+// ownerOf returns the owner recorded for records created by the current identity of the stub
+func ownerOf(stub *shimtest.MockStub, t *testing.T) string {
+	ci, err := cid.New(stub)
+	if err != nil {
+		t.Fatalf("failed to read client identity - %s", err.Error())
+	}
+
+	mspID, _ := ci.GetMSPID()
+	certID, _ := ci.GetID()
+
+	return mspID + "::" + certID
+}
+
+func TestSmartContractCreateAssetOwnedByInvoker(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateAsset"), []byte(clrBlue), []byte("5"), []byte(""), []byte("100"),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(getState(stub, 5, t), &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if owner := ownerOf(stub, t); a.Owner != owner {
+		t.Fatalf(errExpect, owner, a.Owner)
+	}
+}
+
+func TestSmartContractCreateAssetForOthers(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateAsset"), []byte(clrBlue), []byte("5"), []byte(ownrDavid), []byte("100"),
+	}); res.Status == shim.OK {
+		t.Fatalf(`a non admin should not create assets owned by others`)
+	}
+}
+
+func TestSmartContractOwnerOnlyMutations(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateAsset"), []byte(clrBlue), []byte("5"), []byte(""), []byte("100"),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub, t)
+	for i, args := range [][]string{
+		{"TransferAsset", "5", bob},
+		{"UpdateAsset", clrBrown, "5", bob, "1"},
+		{"ChangeAssetColour", "5", clrBrown},
+		{"ChangeAssetValue", "5", "1"},
+		{"DeleteAsset", "5"},
+	} {
+		if res := stub.MockInvoke(strconv.Itoa(i+2), toArgs(args)); res.Status == shim.OK {
+			t.Fatalf(`%s by a non owner should be rejected`, args[0])
+		}
+	}
+
+	stub.Creator = aliceIdentity
+	if res := stub.MockInvoke(`7`, toArgs([]string{"TransferAsset", "5", bob})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = bobIdentity
+	if res := stub.MockInvoke(`8`, toArgs([]string{"ChangeAssetValue", "5", "1"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = adminIdentity
+	if res := stub.MockInvoke(`9`, toArgs([]string{"DeleteAsset", "5"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func TestSmartContractAdminsOfOtherOrgs(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+
+	if res := stub.MockInvoke(`1`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = carolIdentity
+	carol := ownerOf(stub, t)
+	if res := stub.MockInvoke(`2`, toArgs([]string{"CreateAsset", clrBlue, "6", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// the admins of an org that is not an admin org only administer the records of their org
+	stub.Creator = newIdentity("Org2MSP", "admin", roleAdmin)
+	for i, args := range [][]string{
+		{"CreateAsset", clrBlue, "7", ownrDavid, "100"},
+		{"TransferAsset", "5", carol},
+		{"UpdateAsset", clrBrown, "5", carol, "1"},
+		{"DeleteAsset", "5"},
+	} {
+		if res := stub.MockInvoke(strconv.Itoa(i+3), toArgs(args)); DecodeError(res.Message).Code != CodeForbidden {
+			t.Fatalf(errExpect, CodeForbidden, res.Message)
+		}
+	}
+
+	if res := stub.MockInvoke(`7`, toArgs([]string{"ChangeAssetValue", "6", "1"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.MockInvoke(`8`, toArgs([]string{"DeleteAsset", "6"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func toArgs(args []string) [][]byte {
+	byts := make([][]byte, len(args))
+	for i, arg := range args {
+		byts[i] = []byte(arg)
+	}
+
+	return byts
+}
@@ -0,0 +1,81 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	// maxPageSize bounds the number of records a paginated query may return at once
+	maxPageSize = 1000
+	// defaultMaxQueryResults caps the unpaginated GetAll queries unless configured otherwise
+	defaultMaxQueryResults = 10000
+)
+
+// Page is a page of records of a single asset kind. Bookmark resumes the query on the
+// next call and is empty once the last page has been returned.
+type Page struct {
+	Bookmark            string      `json:"bookmark"`
+	FetchedRecordsCount int         `json:"fetchedRecordsCount"`
+	Records             interface{} `json:"records"`
+}
+
+func validPageSize(pageSize int) error {
+	if pageSize < 1 || pageSize > maxPageSize {
+		return errorf(CodeInvalid, `page size %d should be between 1 and %d`, pageSize, maxPageSize)
+	}
+
+	return nil
+}
+
+// page returns the records of the kind in key order. Records live under composite keys,
+// hence the partial composite key flavour of the paginated range query is used.
+func (s store[T, P]) page(ctx contractapi.TransactionContextInterface, pageSize int, bookmark string) (*Page, error) {
+	if err := validPageSize(pageSize); err != nil {
+		return nil, err
+	}
+
+	itr, meta, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(s.kind, []string{}, int32(pageSize), bookmark)
+	if err != nil {
+		return nil, fmt.Errorf(`get %s state by partial composite key with pagination failed - %w`, s.kind, err)
+	}
+	defer itr.Close()
+
+	rs, err := s.decode(itr, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Bookmark:            meta.GetBookmark(),
+		FetchedRecordsCount: int(meta.GetFetchedRecordsCount()),
+		Records:             rs,
+	}, nil
+}
+
+// decode unmarshals the records of a query result and fails once more than limit
+// records are read, where a limit of 0 reads all of them
+func (s store[T, P]) decode(itr shim.StateQueryIteratorInterface, limit int) ([]*T, error) {
+	rs := []*T{}
+	for itr.HasNext() {
+		if limit > 0 && len(rs) == limit {
+			return nil, errorf(CodeInvalid, `query returned more than %d %s records, use the paginated query instead`, limit, s.kind)
+		}
+
+		res, err := itr.Next()
+		if err != nil {
+			return nil, fmt.Errorf(`iterating next %s query result failed - %w`, s.kind, err)
+		}
+
+		var r T
+		if err = json.Unmarshal(res.Value, &r); err != nil {
+			return nil, fmt.Errorf(`unmarshal of %s failed - %w`, s.kind, err)
+		}
+
+		rs = append(rs, &r)
+	}
+
+	return rs, nil
+}
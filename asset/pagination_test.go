@@ -0,0 +1,73 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"testing"
+)
+
+func TestSmartContractGetAssetsWithPagination(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	first := getAssetsPage(stub, "2", ``, t)
+	if first.FetchedRecordsCount != 2 || first.Bookmark == `` {
+		t.Fatalf(`expected 2 assets with a bookmark, got %d with bookmark %q`, first.FetchedRecordsCount, first.Bookmark)
+	}
+
+	second := getAssetsPage(stub, "2", first.Bookmark, t)
+	if second.FetchedRecordsCount != 1 || second.Bookmark != `` {
+		t.Fatalf(`expected the last asset without a bookmark, got %d with bookmark %q`, second.FetchedRecordsCount, second.Bookmark)
+	}
+
+	out := append(first.Records, second.Records...)
+	if !sameRecords(marshalAssets(), marshal(out, t), t) {
+		t.Fatalf(errExpect, marshalAssets(), marshal(out, t))
+	}
+}
+
+func TestSmartContractGetAssetsWithInvalidPageSize(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "GetAssetsWithPagination", kindAsset, "0", ``); res.Status == shim.OK {
+		t.Fatalf(`pagination with an empty page should fail`)
+	}
+}
+
+func TestSmartContractGetAllAssetsCap(t *testing.T) {
+	assetCC, err := contractapi.NewChaincode(&SmartContract{AdminMSPs: []string{testMSP}, MaxQueryResults: 2})
+	if err != nil {
+		t.Fatalf("error creating asset chaincode - %s", err.Error())
+	}
+
+	stub := shimtest.NewMockStub("mockStub", assetCC)
+	stub.Creator = adminIdentity
+	testInitLedger(stub, t)
+
+	if res := stub.MockInvoke(`1`, [][]byte{[]byte("GetAllAssets")}); res.Status == shim.OK {
+		t.Fatalf(`querying more assets than the configured cap should fail`)
+	}
+}
+
+type assetPage struct {
+	Bookmark            string  `json:"bookmark"`
+	FetchedRecordsCount int     `json:"fetchedRecordsCount"`
+	Records             []Asset `json:"records"`
+}
+
+func getAssetsPage(stub *queryStub, pageSize, bookmark string, t *testing.T) assetPage {
+	res := stub.invoke(`1`, "GetAssetsWithPagination", kindAsset, pageSize, bookmark)
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var page assetPage
+	if err := json.Unmarshal(res.Payload, &page); err != nil {
+		t.Fatalf("failed to unmarshal page - %s", err.Error())
+	}
+
+	return page
+}
@@ -0,0 +1,113 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"sort"
+	"strings"
+)
+
+// Rule admits the clients which belong to one of the MSPs, if any is given, and whose
+// certificate carries all of the attributes
+type Rule struct {
+	Attributes map[string]string `json:"attributes,omitempty"`
+	MSPIDs     []string          `json:"mspIds,omitempty"`
+}
+
+// Policy maps transaction names to the rule their invokers must satisfy, where
+// transactions without a rule are open to every member of the channel
+type Policy map[string]Rule
+
+// DefaultPolicy restricts the administrative transactions to admins. Admins of any org may
+// invoke the ones acting on single records, which then check that the admin belongs to the
+// org of the owner, while the ones acting on the whole channel require an admin of the
+// admin orgs, of which there are none if no admin org is given.
+func DefaultPolicy(adminMSPs ...string) Policy {
+	orgAdmin := Rule{Attributes: map[string]string{roleAttr: roleAdmin}}
+	// an empty list of MSPs would admit every org, whereas no client belongs to an empty MSP ID
+	channelAdmin := Rule{Attributes: orgAdmin.Attributes, MSPIDs: []string{``}}
+	if len(adminMSPs) > 0 {
+		channelAdmin.MSPIDs = adminMSPs
+	}
+
+	return Policy{
+		`InitLedger`:                  channelAdmin,
+		`DeleteAsset`:                 orgAdmin,
+		`DeleteVehicle`:               orgAdmin,
+		`DeleteBook`:                  orgAdmin,
+		`DeleteHouse`:                 orgAdmin,
+		`PurgeAsset`:                  orgAdmin,
+		`MigrateLegacyKeys`:           channelAdmin,
+		`RebuildIndexes`:              channelAdmin,
+		`ResetAssetEndorsementPolicy`: channelAdmin,
+	}
+}
+
+func (s *SmartContract) policy() Policy {
+	if s.Policy != nil {
+		return s.Policy
+	}
+
+	return DefaultPolicy(s.AdminMSPs...)
+}
+
+// GetBeforeTransaction enforces the access policy before every transaction
+func (s *SmartContract) GetBeforeTransaction() interface{} {
+	return s.authorize
+}
+
+func (s *SmartContract) authorize(ctx contractapi.TransactionContextInterface) error {
+	if tc, ok := ctx.(*txContext); ok {
+		tc.adminMSPs = s.AdminMSPs
+	}
+
+	fn, _ := ctx.GetStub().GetFunctionAndParameters()
+	// transactions may be qualified with the contract name
+	if i := strings.LastIndex(fn, `:`); i >= 0 {
+		fn = fn[i+1:]
+	}
+
+	rule, ok := s.policy()[fn]
+	if !ok {
+		return nil
+	}
+
+	if err := rule.check(ctx); err != nil {
+		return errorf(CodeForbidden, `not allowed to invoke %s - %w`, fn, err)
+	}
+
+	return nil
+}
+
+func (r Rule) check(ctx contractapi.TransactionContextInterface) error {
+	ci := ctx.GetClientIdentity()
+	if ci == nil {
+		return fmt.Errorf(`client identity of the transaction is not available`)
+	}
+
+	if len(r.MSPIDs) > 0 {
+		mspID, err := ci.GetMSPID()
+		if err != nil {
+			return fmt.Errorf(`get msp id of the client failed - %w`, err)
+		}
+
+		if !contains(r.MSPIDs, mspID) {
+			return fmt.Errorf(`msp %s is not permitted`, mspID)
+		}
+	}
+
+	// attributes are asserted in order so that every peer reports the same denial
+	attrs := make([]string, 0, len(r.Attributes))
+	for attr := range r.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Strings(attrs)
+
+	for _, attr := range attrs {
+		if err := ci.AssertAttributeValue(attr, r.Attributes[attr]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
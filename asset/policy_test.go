@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"testing"
+)
+
+func newPolicyStub(p Policy, t *testing.T) *shimtest.MockStub {
+	cc, err := contractapi.NewChaincode(&SmartContract{AdminMSPs: []string{testMSP}, Policy: p})
+	if err != nil {
+		t.Fatalf("error creating asset chaincode - %s", err.Error())
+	}
+
+	return shimtest.NewMockStub("policyStub", cc)
+}
+
+func TestSmartContractDefaultPolicy(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = aliceIdentity
+
+	res := stub.MockInvoke(`1`, [][]byte{[]byte("InitLedger")})
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	// owners may still not delete their assets unless they are admins
+	if res = stub.MockInvoke(`2`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res = stub.MockInvoke(`3`, toArgs([]string{"DeleteAsset", "5"}))
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	// admins of orgs other than the admin orgs may not administer the channel
+	stub.Creator = newIdentity("Org2MSP", "admin", roleAdmin)
+	res = stub.MockInvoke(`4`, [][]byte{[]byte("InitLedger")})
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	stub.Creator = adminIdentity
+	if res = stub.MockInvoke(`5`, [][]byte{[]byte("InitLedger")}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func TestSmartContractDefaultPolicyWithoutAdminMSPs(t *testing.T) {
+	stub := newPolicyStub(DefaultPolicy(), t)
+	stub.Creator = adminIdentity
+
+	res := stub.MockInvoke(`1`, [][]byte{[]byte("InitLedger")})
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+}
+
+func TestSmartContractPolicyByMSP(t *testing.T) {
+	stub := newPolicyStub(Policy{`CreateAsset`: {MSPIDs: []string{"Org2MSP"}}}, t)
+	stub.Creator = aliceIdentity
+
+	res := stub.MockInvoke(`1`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"}))
+	if res.Status == shim.OK || DecodeError(res.Message).Code != CodeForbidden {
+		t.Fatalf(errExpect, CodeForbidden, res.Message)
+	}
+
+	stub.Creator = newIdentity("Org2MSP", "carol", "")
+	if res = stub.MockInvoke(`2`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// the configured policy replaces the default one, which restricts deletions to admins
+	if res = stub.MockInvoke(`3`, toArgs([]string{"DeleteAsset", "5"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
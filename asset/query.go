@@ -0,0 +1,70 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// design documents and index names as packaged under META-INF/statedb/couchdb/indexes
+var (
+	ownerIndex = []string{`_design/indexOwnerDoc`, `indexOwner`}
+	colorIndex = []string{`_design/indexColorDoc`, `indexColor`}
+)
+
+// richQuery is a CouchDB query where keys are ordered alphabetically to keep it deterministic
+type richQuery struct {
+	Selector map[string]interface{} `json:"selector"`
+	UseIndex []string               `json:"use_index,omitempty"`
+}
+
+// query runs a CouchDB selector restricted to the records of the kind. It is only
+// supported by peers running CouchDB as the state database.
+func (s store[T, P]) query(ctx contractapi.TransactionContextInterface, selector map[string]interface{}, index []string, pageSize int, bookmark string) (*Page, error) {
+	if err := validPageSize(pageSize); err != nil {
+		return nil, err
+	}
+
+	q, err := json.Marshal(richQuery{Selector: selector, UseIndex: index})
+	if err != nil {
+		return nil, fmt.Errorf(`marshal %s query failed - %w`, s.kind, err)
+	}
+
+	itr, meta, err := ctx.GetStub().GetQueryResultWithPagination(string(q), int32(pageSize), bookmark)
+	if err != nil {
+		return nil, fmt.Errorf(`get %s query result with pagination failed - %w`, s.kind, err)
+	}
+	defer itr.Close()
+
+	rs, err := s.decode(itr, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Bookmark:            meta.GetBookmark(),
+		FetchedRecordsCount: int(meta.GetFetchedRecordsCount()),
+		Records:             rs,
+	}, nil
+}
+
+// querySelector runs a selector supplied by the client, which is combined with the
+// kind of the store so that it can not match the records of other kinds
+func (s store[T, P]) querySelector(ctx contractapi.TransactionContextInterface, selectorJSON string, pageSize int, bookmark string) (*Page, error) {
+	var selector map[string]interface{}
+	if err := json.Unmarshal([]byte(selectorJSON), &selector); err != nil {
+		return nil, errorf(CodeInvalid, `selector is not a valid JSON object - %w`, err)
+	}
+
+	return s.query(ctx, map[string]interface{}{
+		`docType`: s.kind,
+		`$and`:    []interface{}{selector},
+	}, nil, pageSize, bookmark)
+}
+
+func (s store[T, P]) queryByField(ctx contractapi.TransactionContextInterface, field, val string, index []string, pageSize int, bookmark string) (*Page, error) {
+	return s.query(ctx, map[string]interface{}{
+		`docType`: s.kind,
+		field:     val,
+	}, index, pageSize, bookmark)
+}
@@ -0,0 +1,57 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+func TestSmartContractQueryAssetsByOwner(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// a vehicle of the same owner must not be returned by an asset query
+	if res := stub.invoke(`2`, "CreateVehicle", clrBlue, "2", "Jane Doe", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	page := queryAssets(stub, t, "QueryAssetsByOwner", kindAsset, "Jane Doe", "10", ``)
+	if page.FetchedRecordsCount != 1 || !sameRecords(marshal(assets[1:2], t), marshal(page.Records, t), t) {
+		t.Fatalf(errExpect, marshal(assets[1:2], t), marshal(page.Records, t))
+	}
+}
+
+func TestSmartContractQueryAssetsBySelector(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "InitLedger"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	page := queryAssets(stub, t, "QueryAssets", kindAsset, `{"color":"yellow","owner":"Bill"}`, "10", ``)
+	if page.FetchedRecordsCount != 1 || page.Records[0].ID != 3 {
+		t.Fatalf(errExpect, marshal(assets[2:], t), marshal(page.Records, t))
+	}
+}
+
+func TestSmartContractQueryAssetsInvalidSelector(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "QueryAssets", kindAsset, `{"color":`, "10", ``); res.Status == shim.OK {
+		t.Fatalf(`query with a malformed selector should fail`)
+	}
+}
+
+func queryAssets(stub *queryStub, t *testing.T, args ...string) assetPage {
+	res := stub.invoke(`1`, args...)
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var page assetPage
+	if err := json.Unmarshal(res.Payload, &page); err != nil {
+		t.Fatalf("failed to unmarshal page - %s", err.Error())
+	}
+
+	return page
+}
@@ -0,0 +1,265 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// priceKey is the transient map entry carrying the price agreed by a party of a sale
+const priceKey = `asset_price`
+
+// object types of the keys of a sale, where the prices are kept in the implicit collection
+// of each party and the bids publicly name the buyers without revealing their prices. Each
+// buyer bids and prices under its own key, so that no client can replace the bid of another.
+const (
+	sellPriceKey = `sell~asset`
+	buyPriceKey  = `buy~asset~buyer`
+	bidKey       = `bid~asset~buyer`
+)
+
+// PriceAgreement is the price a party agrees to trade an asset for, which both parties
+// must submit identically for their private data hashes to match
+type PriceAgreement struct {
+	ID      int    `json:"id"`
+	Price   int    `json:"price"`
+	TradeID string `json:"tradeId"`
+}
+
+// Bid names a buyer who agreed to buy an asset
+type Bid struct {
+	Buyer string `json:"buyer"`
+	ID    int    `json:"id"`
+}
+
+// AgreeToSell records the price the owner of the asset agrees to sell it for in the
+// implicit collection of the org of the owner
+func (s *SmartContract) AgreeToSell(ctx contractapi.TransactionContextInterface, id int) error {
+	a, err := assetFamily.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller != a.Owner {
+		return errorf(CodeForbidden, `only the owner can agree to sell asset %d`, id)
+	}
+
+	k, err := key(ctx, sellPriceKey, id)
+	if err != nil {
+		return err
+	}
+
+	return putPrice(ctx, implicitCollection(ownerMSP(caller)), k, id)
+}
+
+// AgreeToBuy records the price the invoker agrees to buy the asset for in the implicit
+// collection of the org of the invoker, and bids on the asset next to any other buyer. The
+// price may then only be removed with the endorsement of the org of the invoker.
+func (s *SmartContract) AgreeToBuy(ctx contractapi.TransactionContextInterface, id int) error {
+	a, err := assetFamily.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller == a.Owner {
+		return errorf(CodeForbidden, `the owner can not agree to buy asset %d`, id)
+	}
+
+	buyerMSP := ownerMSP(caller)
+	collection := implicitCollection(buyerMSP)
+	k, err := buyerKey(ctx, buyPriceKey, id, caller)
+	if err != nil {
+		return err
+	}
+
+	if err = putPrice(ctx, collection, k, id); err != nil {
+		return err
+	}
+
+	// implicit collections have no endorsement policy of their own
+	policy, err := orgPolicy(buyerMSP)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().SetPrivateDataValidationParameter(collection, k, policy); err != nil {
+		return fmt.Errorf(`set endorsement policy failed for the price of asset %d - %w`, id, err)
+	}
+
+	byts, err := json.Marshal(Bid{Buyer: caller, ID: id})
+	if err != nil {
+		return fmt.Errorf(`marshal bid failed for asset %d - %w`, id, err)
+	}
+
+	if k, err = buyerKey(ctx, bidKey, id, caller); err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().PutState(k, byts); err != nil {
+		return fmt.Errorf(`put bid failed for asset %d - %w`, id, err)
+	}
+
+	return nil
+}
+
+// TransferAssetWithAgreement transfers the asset to the given buyer, given that the owner
+// invokes it, the buyer bid on the asset and both parties agreed on the same price. Removing
+// the price of the buyer from its implicit collection requires the endorsement of the org of
+// the buyer.
+func (s *SmartContract) TransferAssetWithAgreement(ctx contractapi.TransactionContextInterface, id int, buyer string) error {
+	a, err := assetFamily.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	caller, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if caller != a.Owner {
+		return errorf(CodeForbidden, `only the owner can sell asset %d`, id)
+	}
+
+	buyerMSP := ownerMSP(buyer)
+	if buyerMSP == `` {
+		return errorf(CodeInvalid, `buyer %s is not a client identity`, buyer)
+	}
+
+	bk, err := buyerKey(ctx, bidKey, id, buyer)
+	if err != nil {
+		return err
+	}
+
+	bid, err := ctx.GetStub().GetState(bk)
+	if err != nil {
+		return fmt.Errorf(`get bid failed for asset %d - %w`, id, err)
+	}
+
+	if bid == nil {
+		return errorf(CodeNotFound, `%s did not agree to buy asset %d`, buyer, id)
+	}
+
+	// peers of both orgs endorse the sale, hence the collections are derived from the parties
+	// rather than from the org of the peer, which only needs the hashes of the prices
+	sellerCollection := implicitCollection(ownerMSP(a.Owner))
+	buyerCollection := implicitCollection(buyerMSP)
+
+	sk, err := key(ctx, sellPriceKey, id)
+	if err != nil {
+		return err
+	}
+
+	pk, err := buyerKey(ctx, buyPriceKey, id, buyer)
+	if err != nil {
+		return err
+	}
+
+	sellHash, err := priceHash(ctx, sellerCollection, sk, id)
+	if err != nil {
+		return err
+	}
+
+	buyHash, err := priceHash(ctx, buyerCollection, pk, id)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(sellHash, buyHash) {
+		return errorf(CodeConflict, `seller and buyer of asset %d did not agree on the price`, id)
+	}
+
+	if err = assetFamily.transfer(ctx, id, buyer, anyVersion); err != nil {
+		return err
+	}
+
+	for _, p := range []struct{ collection, key string }{
+		{collection: sellerCollection, key: sk},
+		{collection: buyerCollection, key: pk},
+	} {
+		if err = ctx.GetStub().DelPrivateData(p.collection, p.key); err != nil {
+			return fmt.Errorf(`deleting price of asset %d from %s failed - %w`, id, p.collection, err)
+		}
+	}
+
+	if err = ctx.GetStub().DelState(bk); err != nil {
+		return fmt.Errorf(`deleting bid of asset %d failed - %w`, id, err)
+	}
+
+	return nil
+}
+
+// buyerKey returns the key of the price or the bid of a buyer of an asset
+func buyerKey(ctx contractapi.TransactionContextInterface, kind string, id int, buyer string) (string, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(kind, []string{strconv.Itoa(id), buyer})
+	if err != nil {
+		return ``, fmt.Errorf(`create %s key failed for asset %d - %w`, kind, id, err)
+	}
+
+	return k, nil
+}
+
+// putPrice stores the price agreement passed in the transient map under the key in the
+// implicit collection of the org of the invoker
+func putPrice(ctx contractapi.TransactionContextInterface, collection, k string, id int) error {
+	tm, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf(`get transient map failed - %w`, err)
+	}
+
+	in, ok := tm[priceKey]
+	if !ok {
+		return errorf(CodeInvalid, `%s is missing in the transient map`, priceKey)
+	}
+
+	var p PriceAgreement
+	if err = json.Unmarshal(in, &p); err != nil {
+		return errorf(CodeInvalid, `%s is not a JSON price agreement - %w`, priceKey, err)
+	}
+
+	if p.Price <= 0 {
+		return errorf(CodeInvalid, `price %d must be positive`, p.Price)
+	}
+
+	if p.TradeID == `` {
+		return errorf(CodeInvalid, `trade id of the price agreement is required`)
+	}
+	p.ID = id
+
+	// the price is re-encoded so that equal agreements of both parties hash equally
+	byts, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf(`marshal price agreement failed - %w`, err)
+	}
+
+	if err = ctx.GetStub().PutPrivateData(collection, k, byts); err != nil {
+		return fmt.Errorf(`put price failed for asset %d - %w`, id, err)
+	}
+
+	return nil
+}
+
+func priceHash(ctx contractapi.TransactionContextInterface, collection, k string, id int) ([]byte, error) {
+	hash, err := ctx.GetStub().GetPrivateDataHash(collection, k)
+	if err != nil {
+		return nil, fmt.Errorf(`get price hash of asset %d from %s failed - %w`, id, collection, err)
+	}
+
+	if hash == nil {
+		return nil, errorf(CodeNotFound, `no price agreed for asset %d in %s`, id, collection)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,144 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+const buyerMSP = "Org2MSP"
+
+var carolIdentity = newIdentity(buyerMSP, "carol", "")
+
+// testAgreements lets alice of Org1 own asset 5 and both parties agree on a price
+func testAgreements(stub *queryStub, sellPrice, buyPrice string, t *testing.T) {
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.TransientMap = map[string][]byte{priceKey: []byte(sellPrice)}
+	if res := stub.invoke(`2`, "AgreeToSell", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = carolIdentity
+	stub.TransientMap = map[string][]byte{priceKey: []byte(buyPrice)}
+	if res := stub.invoke(`3`, "AgreeToBuy", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
+
+func TestSmartContractTransferAssetWithAgreement(t *testing.T) {
+	stub := newQueryStub()
+	testAgreements(stub, `{"price":900,"tradeId":"t1"}`, `{"price":900,"tradeId":"t1"}`, t)
+	carol := ownerOf(stub.MockStub, t)
+
+	// only the owner can sell
+	if res := stub.invoke(`4`, "TransferAssetWithAgreement", "5", carol); res.Status == shim.OK {
+		t.Fatalf(`the buyer should not be able to complete the sale`)
+	}
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`5`, "TransferAssetWithAgreement", "5", carol); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`6`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(res.Payload, &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if a.Owner != carol {
+		t.Fatalf(errExpect, carol, a.Owner)
+	}
+
+	if len(stub.PvtState[implicitCollection(buyerMSP)]) != 0 {
+		t.Fatalf(`price of the buyer should be removed after the sale`)
+	}
+}
+
+func TestSmartContractTransferAssetWithoutAgreement(t *testing.T) {
+	stub := newQueryStub()
+	testAgreements(stub, `{"price":900,"tradeId":"t1"}`, `{"price":800,"tradeId":"t1"}`, t)
+	carol := ownerOf(stub.MockStub, t)
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`4`, "TransferAssetWithAgreement", "5", carol); res.Status == shim.OK {
+		t.Fatalf(`an asset should not be sold without agreeing on the price`)
+	}
+}
+
+func TestSmartContractTransferAssetWithAgreementOfOneBuyer(t *testing.T) {
+	stub := newQueryStub()
+	testAgreements(stub, `{"price":900,"tradeId":"t1"}`, `{"price":900,"tradeId":"t1"}`, t)
+	carol := ownerOf(stub.MockStub, t)
+
+	// another member of the org of the buyer bids next to the buyer instead of replacing its bid
+	stub.Creator = newIdentity(buyerMSP, "dave", "")
+	dave := ownerOf(stub.MockStub, t)
+	stub.TransientMap = map[string][]byte{priceKey: []byte(`{"price":800,"tradeId":"t1"}`)}
+	if res := stub.invoke(`4`, "AgreeToBuy", "5"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// removing the price of a buyer requires the endorsement of its org
+	collection := implicitCollection(buyerMSP)
+	for _, buyer := range []string{carol, dave} {
+		k, err := stub.CreateCompositeKey(buyPriceKey, []string{"5", buyer})
+		if err != nil {
+			t.Fatalf("failed to create key - %s", err.Error())
+		}
+
+		ep, err := statebased.NewStateEP(stub.EndorsementPolicies[collection][k])
+		if err != nil {
+			t.Fatalf("failed to unmarshal endorsement policy - %s", err.Error())
+		}
+
+		if orgs := ep.ListOrgs(); len(orgs) != 1 || orgs[0] != buyerMSP {
+			t.Fatalf(errExpect, buyerMSP, orgs)
+		}
+	}
+
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`5`, "TransferAssetWithAgreement", "5", dave); DecodeError(res.Message).Code != CodeConflict {
+		t.Fatalf(errExpect, CodeConflict, res.Message)
+	}
+
+	if res := stub.invoke(`6`, "TransferAssetWithAgreement", "5", carol); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`7`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(res.Payload, &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if a.Owner != carol {
+		t.Fatalf(errExpect, carol, a.Owner)
+	}
+}
+
+func TestSmartContractAgreeToSellMalformed(t *testing.T) {
+	stub := newQueryStub()
+	stub.Creator = aliceIdentity
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", "", "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.TransientMap = map[string][]byte{priceKey: []byte(`{"price":`)}
+	if res := stub.invoke(`2`, "AgreeToSell", "5"); DecodeError(res.Message).Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res.Message)
+	}
+}
@@ -0,0 +1,263 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"strconv"
+)
+
+// sharesKey is the object type of the share ledgers, keyed by the kind and id of the record
+const sharesKey = `shares~kind~id`
+
+// ShareLedger splits the ownership of a record into units, whose total is fixed on issue
+type ShareLedger struct {
+	Holders map[string]int `json:"holders"`
+	ID      int            `json:"id"`
+	Kind    string         `json:"kind"`
+	Total   int            `json:"total"`
+}
+
+func (f family[T, P]) sharesKey(ctx contractapi.TransactionContextInterface, id int) (string, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(sharesKey, []string{f.store.kind, strconv.Itoa(id)})
+	if err != nil {
+		return ``, fmt.Errorf(`create shares key failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return k, nil
+}
+
+// findShares returns the share ledger of a record or nil if it is not fractionalised
+func (f family[T, P]) findShares(ctx contractapi.TransactionContextInterface, id int) (*ShareLedger, error) {
+	k, err := f.sharesKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	byts, err := ctx.GetStub().GetState(k)
+	if err != nil {
+		return nil, fmt.Errorf(`get shares failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	if byts == nil {
+		return nil, nil
+	}
+
+	var l ShareLedger
+	if err = json.Unmarshal(byts, &l); err != nil {
+		return nil, fmt.Errorf(`unmarshal shares failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return &l, nil
+}
+
+// unfractionalised fails if the record is owned through shares, as its owner can not
+// hand it over as a whole then
+func (f family[T, P]) unfractionalised(ctx contractapi.TransactionContextInterface, id int) error {
+	l, err := f.findShares(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if l != nil {
+		return errorf(CodeConflict, `%s %d is fractionalised and can only change hands through its shares`, f.store.kind, id)
+	}
+
+	return nil
+}
+
+// issueShares fractionalises a record into units, which are all held by its owner
+func (f family[T, P]) issueShares(ctx contractapi.TransactionContextInterface, id int, units int) error {
+	r, err := f.store.get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = authorizeOwner(ctx, P(r).owner()); err != nil {
+		return fmt.Errorf(`issue shares of %s %d failed - %w`, f.store.kind, id, err)
+	}
+
+	if err = f.unlocked(ctx, id); err != nil {
+		return err
+	}
+
+	if err = f.unfractionalised(ctx, id); err != nil {
+		return err
+	}
+
+	if units <= 0 {
+		return errorf(CodeInvalid, `number of shares %d must be positive`, units)
+	}
+
+	if err = f.putShares(ctx, &ShareLedger{Holders: map[string]int{P(r).owner(): units}, ID: id, Kind: f.store.kind, Total: units}); err != nil {
+		return err
+	}
+
+	return f.emitShares(ctx, EventSharesIssued, id, ``, P(r).owner(), units)
+}
+
+// transferShares moves units of a record from the invoker to the recipient
+func (f family[T, P]) transferShares(ctx contractapi.TransactionContextInterface, id int, to string, units int) error {
+	l, err := f.getShares(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	from, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if units <= 0 {
+		return errorf(CodeInvalid, `number of shares %d must be positive`, units)
+	}
+
+	if to == `` {
+		return errorf(CodeInvalid, `recipient of the shares is required`)
+	}
+
+	if l.Holders[from] < units {
+		return errorf(CodeConflict, `%s holds %d of the %d shares to transfer`, from, l.Holders[from], units)
+	}
+
+	l.Holders[from] -= units
+	l.Holders[to] += units
+	if l.Holders[from] == 0 {
+		delete(l.Holders, from)
+	}
+
+	if err = f.putShares(ctx, l); err != nil {
+		return err
+	}
+
+	return f.emitShares(ctx, EventSharesTransferred, id, from, to, units)
+}
+
+// emitShares reports units of the shares of a record moving between holders
+func (f family[T, P]) emitShares(ctx contractapi.TransactionContextInterface, op string, id int, from, to string, units int) error {
+	return emitEvent(ctx, Event{
+		Changed:   []string{`holders`},
+		ID:        id,
+		Kind:      f.store.kind,
+		NewOwner:  to,
+		OldOwner:  from,
+		Operation: op,
+		Units:     units,
+		Version:   EventVersion,
+	})
+}
+
+// redeemShares merges the shares of a record back into a whole owned by the invoker,
+// given that the invoker holds all of them
+func (f family[T, P]) redeemShares(ctx contractapi.TransactionContextInterface, id int) error {
+	l, err := f.getShares(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	holder, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if l.Holders[holder] != l.Total {
+		return errorf(CodeConflict, `%s holds %d of the %d shares of %s %d`, holder, l.Holders[holder], l.Total, f.store.kind, id)
+	}
+
+	// the shares authorize the holder to take over the record
+	if err = f.change(ctx, id, EventAssetTransferred, f.handOver(ctx, holder)); err != nil {
+		return err
+	}
+
+	k, err := f.sharesKey(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = ctx.GetStub().DelState(k); err != nil {
+		return fmt.Errorf(`delete shares failed for %s %d - %w`, f.store.kind, id, err)
+	}
+
+	return nil
+}
+
+func (f family[T, P]) getShares(ctx contractapi.TransactionContextInterface, id int) (*ShareLedger, error) {
+	l, err := f.findShares(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if l == nil {
+		return nil, errorf(CodeNotFound, `%s %d is not fractionalised`, f.store.kind, id)
+	}
+
+	return l, nil
+}
+
+// putShares stores a share ledger given that its units still add up to the total
+func (f family[T, P]) putShares(ctx contractapi.TransactionContextInterface, l *ShareLedger) error {
+	sum := 0
+	for _, units := range l.Holders {
+		sum += units
+	}
+
+	if sum != l.Total {
+		return fmt.Errorf(`shares of %s %d add up to %d instead of %d`, f.store.kind, l.ID, sum, l.Total)
+	}
+
+	k, err := f.sharesKey(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+
+	byts, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf(`marshal shares failed for %s %d - %w`, f.store.kind, l.ID, err)
+	}
+
+	if err = ctx.GetStub().PutState(k, byts); err != nil {
+		return fmt.Errorf(`put shares failed for %s %d - %w`, f.store.kind, l.ID, err)
+	}
+
+	return nil
+}
+
+// IssueShares fractionalises a record of the given kind into units held by its owner,
+// after which it can not be transferred as a whole
+func (s *SmartContract) IssueShares(ctx contractapi.TransactionContextInterface, kind string, id int, units int) error {
+	f, err := familyOf(kind)
+	if err != nil {
+		return err
+	}
+
+	return f.issueShares(ctx, id, units)
+}
+
+// TransferShares moves units of a record of the given kind from the invoker to the recipient
+func (s *SmartContract) TransferShares(ctx contractapi.TransactionContextInterface, kind string, id int, to string, units int) error {
+	f, err := familyOf(kind)
+	if err != nil {
+		return err
+	}
+
+	return f.transferShares(ctx, id, to, units)
+}
+
+// RedeemShares makes the invoker holding all units of a record of the given kind its sole owner
+func (s *SmartContract) RedeemShares(ctx contractapi.TransactionContextInterface, kind string, id int) error {
+	f, err := familyOf(kind)
+	if err != nil {
+		return err
+	}
+
+	return f.redeemShares(ctx, id)
+}
+
+func (s *SmartContract) GetShareholders(ctx contractapi.TransactionContextInterface, kind string, id int) (*ShareLedger, error) {
+	f, err := familyOf(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.getShares(ctx, id)
+}
@@ -0,0 +1,80 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSmartContractHouseShares(t *testing.T) {
+	stub := newMockStub()
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub, t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub, t)
+
+	for i, args := range [][]string{
+		{"CreateHouse", clrBlue, "5", "", "100"},
+		{"IssueShares", kindHouse, "5", "100"},
+		{"TransferShares", kindHouse, "5", bob, "40"},
+	} {
+		if res := stub.MockInvoke(strconv.Itoa(10+i), toArgs(args)); res.Status != shim.OK {
+			t.Fatalf(errOK, res.Status, res.Message)
+		}
+	}
+
+	// share changes are reported like any other change of the record
+	nextEvent(stub, EventAssetCreated, &Event{}, t)
+	for _, in := range []Event{
+		{Changed: []string{"holders"}, ID: 5, Kind: kindHouse, NewOwner: alice, Operation: EventSharesIssued, Units: 100, Version: EventVersion},
+		{Changed: []string{"holders"}, ID: 5, Kind: kindHouse, NewOwner: bob, OldOwner: alice, Operation: EventSharesTransferred, Units: 40, Version: EventVersion},
+	} {
+		var e Event
+		nextEvent(stub, in.Operation, &e, t)
+		if !reflect.DeepEqual(in, e) {
+			t.Fatalf(errExpect, marshal(in, t), marshal(e, t))
+		}
+	}
+
+	if res := stub.MockInvoke(`1`, toArgs([]string{"TransferHouse", "5", bob})); res.Status == shim.OK {
+		t.Fatalf(`a fractionalised house should not be transferred`)
+	}
+
+	res := stub.MockInvoke(`2`, toArgs([]string{"GetShareholders", kindHouse, "5"}))
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var l ShareLedger
+	if err := json.Unmarshal(res.Payload, &l); err != nil {
+		t.Fatalf("failed to unmarshal shares - %s", err.Error())
+	}
+
+	if in := (ShareLedger{Holders: map[string]int{alice: 60, bob: 40}, ID: 5, Kind: kindHouse, Total: 100}); !reflect.DeepEqual(in, l) {
+		t.Fatalf(errExpect, marshal(in, t), res.Payload)
+	}
+
+	stub.Creator = bobIdentity
+	if res = stub.MockInvoke(`3`, toArgs([]string{"TransferShares", kindHouse, "5", alice, "41"})); res.Status == shim.OK {
+		t.Fatalf(`transferring more shares than held should fail`)
+	}
+
+	if res = stub.MockInvoke(`4`, toArgs([]string{"RedeemShares", kindHouse, "5"})); res.Status == shim.OK {
+		t.Fatalf(`redeeming without holding all shares should fail`)
+	}
+
+	if res = stub.MockInvoke(`5`, toArgs([]string{"TransferShares", kindHouse, "5", alice, "40"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	stub.Creator = aliceIdentity
+	if res = stub.MockInvoke(`6`, toArgs([]string{"RedeemShares", kindHouse, "5"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res = stub.MockInvoke(`7`, toArgs([]string{"TransferHouse", "5", bob})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+}
@@ -1,698 +1,283 @@
 package asset
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
-	"strconv"
 )
 
 /* This is a sample chaincode implemented as per the Fabric documentation */
 
 var (
+	assetFamily = newFamily[Asset](kindAsset)
+
 	assets = []Asset{
-		{ID: 1, Color: "blue", Owner: "John Doe", Value: 500},
-		{ID: 2, Color: "red", Owner: "Jane Doe", Value: 600},
-		{ID: 3, Color: "yellow", Owner: "Bill", Value: 450},
+		{ID: 1, Color: "blue", DocType: kindAsset, Owner: "John Doe", Value: Money{Amount: 50000, Currency: DefaultCurrency}, Version: 1},
+		{ID: 2, Color: "red", DocType: kindAsset, Owner: "Jane Doe", Value: Money{Amount: 60000, Currency: DefaultCurrency}, Version: 1},
+		{ID: 3, Color: "yellow", DocType: kindAsset, Owner: "Bill", Value: Money{Amount: 45000, Currency: DefaultCurrency}, Version: 1},
 	}
 )
 
 type SmartContract struct {
 	contractapi.Contract
+	// MaxQueryResults is the hard cap of records returned by the unpaginated GetAll
+	// transactions, where zero falls back to the default cap
+	MaxQueryResults int
+	// MaxBatchSize bounds the items of the batch transactions and the rows of the import
+	// chunks, where zero falls back to the default bound. Changing it renumbers the chunks,
+	// hence imports in progress must be resumed under the size they started with.
+	MaxBatchSize int
+	// AdminMSPs are the orgs whose admins administer the records of every owner and the
+	// channel, whereas the admins of other orgs only administer the records of their org
+	AdminMSPs []string
+	// Policy gates transactions on the identity of their invokers, where nil falls back
+	// to the default policy
+	Policy Policy
 }
 
 // Asset attributes are defined in alphabetical order to make JSON struct deterministic
 type Asset struct {
-	Color string `json:"color"`
-	ID    int    `json:"id"`
-	Owner string `json:"owner"`
-	Value int    `json:"value"`
+	Color     string `json:"color"`
+	CreatedAt string `json:"createdAt"`
+	CreatedBy string `json:"createdBy"`
+	DocType   string `json:"docType"`
+	ID        int    `json:"id"`
+	Owner     string `json:"owner"`
+	UpdatedAt string `json:"updatedAt"`
+	UpdatedBy string `json:"updatedBy"`
+	Value     Money  `json:"value"`
+	Version   int    `json:"version"`
 }
 
-func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
-	for _, a := range assets {
-		aByts, err := json.Marshal(a)
-		if err != nil {
-			return fmt.Errorf(`marshal asset failed for asset %d - %w`, a.ID, err)
-		}
-
-		if err = ctx.GetStub().PutState(strconv.Itoa(a.ID), aByts); err != nil {
-			return fmt.Errorf(`put asset failed for asset %d - %w`, a.ID, err)
-		}
+func (s *SmartContract) maxQueryResults() int {
+	if s.MaxQueryResults > 0 {
+		return s.MaxQueryResults
 	}
 
-	return nil
+	return defaultMaxQueryResults
 }
 
-func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.AssetExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`create asset failed - %w`, err)
-	}
-
-	if exists {
-		return fmt.Errorf(`asset with id %d already exists`, id)
-	}
-
-	asset := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(asset)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(asset.ID), aByts)
-}
-
-func (s *SmartContract) GetAsset(ctx contractapi.TransactionContextInterface, id int) (*Asset, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
-	if err != nil {
-		return nil, fmt.Errorf(`get state failed for asset %d - %w`, id, err)
-	}
-
-	if aByts == nil {
-		return nil, fmt.Errorf(`asset does not exist for id %d`, id)
-	}
-
-	var a Asset
-	if err = json.Unmarshal(aByts, &a); err != nil {
-		return nil, fmt.Errorf(`unmarshal asset failed for asset %d - %w`, id, err)
-	}
-
-	return &a, nil
-}
-
-func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.AssetExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking asset existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`asset with id %d does not exist`, id)
-	}
-
-	a := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(a.ID), aByts)
-}
-
-func (s *SmartContract) DeleteAsset(ctx contractapi.TransactionContextInterface, id int) error {
-	exists, err := s.AssetExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking asset existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`asset with id %d does not exist`, id)
-	}
-
-	return ctx.GetStub().DelState(strconv.Itoa(id))
-}
-
-func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
-	a, err := s.GetAsset(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
-
-	a.Owner = newOwner
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
-}
-
-func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
-	// ranging with empty start and end keys returns all assets in chaincode namespace
-	itr, err := ctx.GetStub().GetStateByRange(``, ``)
-	if err != nil {
-		return nil, fmt.Errorf(`get state by range faied - %w`, err)
-	}
-	defer itr.Close()
-
-	var ats []*Asset
-	for itr.HasNext() {
-		res, err := itr.Next()
-		if err != nil {
-			return nil, fmt.Errorf(`iterating next query result failed - %w`, err)
-		}
-
-		var a Asset
-		if err = json.Unmarshal(res.Value, &a); err != nil {
-			return nil, fmt.Errorf(`unmarshal failed - %w`, err)
+// InitLedger seeds the ledger with the sample assets, which are created like any other asset
+// and hence fail with ALREADY_EXISTS once seeded
+func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
+	for i := range assets {
+		a := assets[i]
+		if err := assetFamily.createRecord(ctx, &a); err != nil {
+			return fmt.Errorf(`init ledger failed - %w`, err)
 		}
-
-		ats = append(ats, &a)
-	}
-
-	return ats, nil
-}
-
-func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
-	if err != nil {
-		return false, fmt.Errorf(`get state failed for asset %d - %w`, id, err)
-	}
-
-	return aByts != nil, nil
-}
-
-func (s *SmartContract) ChangeAssetColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
-	a, err := s.GetAsset(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
 	}
 
-	a.Color = clr
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
-}
-
-func (s *SmartContract) ChangeAssetValue(ctx contractapi.TransactionContextInterface, id int, val int) error {
-	a, err := s.GetAsset(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
-
-	a.Value = val
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
-}
-
-// Vehicle functions
-
-func (s *SmartContract) CreateVehicle(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.VehicleExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`create vehicle failed - %w`, err)
-	}
-
-	if exists {
-		return fmt.Errorf(`vehicle with id %d already exists`, id)
-	}
-
-	vehicle := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(vehicle)
-	if err != nil {
-		return fmt.Errorf(`marshal vehicle failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(vehicle.ID), aByts)
+	return nil
 }
 
-func (s *SmartContract) GetVehicle(ctx contractapi.TransactionContextInterface, id int) (*Asset, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
-	if err != nil {
-		return nil, fmt.Errorf(`get state failed for vehicle %d - %w`, id, err)
-	}
-
-	if aByts == nil {
-		return nil, fmt.Errorf(`vehicle does not exist for id %d`, id)
-	}
-
-	var a Asset
-	if err = json.Unmarshal(aByts, &a); err != nil {
-		return nil, fmt.Errorf(`unmarshal vehicle failed for vehicle %d - %w`, id, err)
-	}
-
-	return &a, nil
+func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return assetFamily.create(ctx, color, id, owner, val)
 }
 
-func (s *SmartContract) UpdateVehicle(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.VehicleExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking vehicle existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`vehicle with id %d does not exist`, id)
-	}
-
-	a := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal vehicle failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(a.ID), aByts)
+func (s *SmartContract) GetAsset(ctx contractapi.TransactionContextInterface, id int) (*Asset, error) {
+	return assetFamily.get(ctx, id)
 }
 
-func (s *SmartContract) DeleteVehicle(ctx contractapi.TransactionContextInterface, id int) error {
-	exists, err := s.VehicleExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking vehicle existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`vehicle with id %d does not exist`, id)
-	}
-
-	return ctx.GetStub().DelState(strconv.Itoa(id))
+func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return assetFamily.update(ctx, color, id, owner, val, anyVersion)
 }
 
-func (s *SmartContract) TransferVehicle(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
-	a, err := s.GetVehicle(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get vehicle failed - %w`, err)
-	}
-
-	a.Owner = newOwner
-	aByts, err := json.Marshal(a)
+// UpdateAssetWithVersion updates the record of the given kind given that it is still at the expected
+// version, failing with a conflict otherwise
+func (s *SmartContract) UpdateAssetWithVersion(ctx contractapi.TransactionContextInterface, kind string, color string, id int, owner string, val string, version int) error {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal vehicle failed - %w`, err)
+		return err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.update(ctx, color, id, owner, val, version)
 }
 
-func (s *SmartContract) GetAllVehicles(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
-	// ranging with empty start and end keys returns all vehicles in chaincode namespace
-	// an identical function of get all assets
-	itr, err := ctx.GetStub().GetStateByRange(``, ``)
-	if err != nil {
-		return nil, fmt.Errorf(`get vehicle state by range faied - %w`, err)
-	}
-	defer itr.Close()
-
-	var ats []*Asset
-	for itr.HasNext() {
-		res, err := itr.Next()
-		if err != nil {
-			return nil, fmt.Errorf(`iterating next vehicle query result failed - %w`, err)
-		}
-
-		var a Asset
-		if err = json.Unmarshal(res.Value, &a); err != nil {
-			return nil, fmt.Errorf(`unmarshal of vehicle failed - %w`, err)
-		}
-
-		ats = append(ats, &a)
-	}
-
-	return ats, nil
+func (s *SmartContract) DeleteAsset(ctx contractapi.TransactionContextInterface, id int) error {
+	return assetFamily.delete(ctx, id)
 }
 
-func (s *SmartContract) VehicleExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
+// RestoreAsset brings the last deleted record of the given kind and id back from the archive as
+// its next version
+func (s *SmartContract) RestoreAsset(ctx contractapi.TransactionContextInterface, kind string, id int) error {
+	f, err := familyOf(kind)
 	if err != nil {
-		return false, fmt.Errorf(`get state failed for vehicle %d - %w`, id, err)
+		return err
 	}
 
-	return aByts != nil, nil
+	return f.restore(ctx, id)
 }
 
-func (s *SmartContract) ChangeVehicleColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
-	a, err := s.GetVehicle(ctx, id)
+// PurgeAsset removes the last deleted record of the given kind and id from the archive for good,
+// which only admins of its owner may do
+func (s *SmartContract) PurgeAsset(ctx contractapi.TransactionContextInterface, kind string, id int) error {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
+		return err
 	}
 
-	a.Color = clr
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.purge(ctx, id)
 }
 
-func (s *SmartContract) ChangeVehicleValue(ctx contractapi.TransactionContextInterface, id int, val int) error {
-	a, err := s.GetVehicle(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
-
-	a.Value = val
-	aByts, err := json.Marshal(a)
+// GetArchivedAssets returns a page of every deletion of the records of the given kind along with
+// the deleted record
+func (s *SmartContract) GetArchivedAssets(ctx contractapi.TransactionContextInterface, kind string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
+		return nil, err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.archived(ctx, pageSize, bookmark)
 }
 
-// Book functions
-
-func (s *SmartContract) CreateBook(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.BookExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`create book failed - %w`, err)
-	}
-
-	if exists {
-		return fmt.Errorf(`book with id %d already exists`, id)
-	}
-
-	book := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(book)
-	if err != nil {
-		return fmt.Errorf(`marshal book failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(book.ID), aByts)
+func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
+	return assetFamily.transfer(ctx, id, newOwner, anyVersion)
 }
 
-func (s *SmartContract) GetBook(ctx contractapi.TransactionContextInterface, id int) (*Asset, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
+// TransferAssetWithVersion transfers the record of the given kind given that it is still at the expected
+// version, failing with a conflict otherwise
+func (s *SmartContract) TransferAssetWithVersion(ctx contractapi.TransactionContextInterface, kind string, id int, newOwner string, version int) error {
+	f, err := familyOf(kind)
 	if err != nil {
-		return nil, fmt.Errorf(`get state failed for book %d - %w`, id, err)
-	}
-
-	if aByts == nil {
-		return nil, fmt.Errorf(`book does not exist for id %d`, id)
-	}
-
-	var a Asset
-	if err = json.Unmarshal(aByts, &a); err != nil {
-		return nil, fmt.Errorf(`unmarshal book failed for book %d - %w`, id, err)
+		return err
 	}
 
-	return &a, nil
+	return f.transfer(ctx, id, newOwner, version)
 }
 
-func (s *SmartContract) UpdateBook(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.BookExists(ctx, id)
+// CreateAssetsBatch creates the records of the given kind of a JSON array of items with their color,
+// id, owner and value, either all of them or none
+func (s *SmartContract) CreateAssetsBatch(ctx contractapi.TransactionContextInterface, kind string, items string) (*BatchReport, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`checking book existence failed - %w`, err)
+		return nil, err
 	}
 
-	if !exists {
-		return fmt.Errorf(`book with id %d does not exist`, id)
-	}
-
-	a := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal book failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(a.ID), aByts)
+	return f.createBatch(ctx, items, s.maxBatchSize())
 }
 
-func (s *SmartContract) DeleteBook(ctx contractapi.TransactionContextInterface, id int) error {
-	exists, err := s.BookExists(ctx, id)
+// UpdateAssetsBatch updates the records of the given kind of a JSON array of items with their color,
+// id, owner, value and optionally their expected version, either all of them or none
+func (s *SmartContract) UpdateAssetsBatch(ctx contractapi.TransactionContextInterface, kind string, items string) (*BatchReport, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`checking book existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`book with id %d does not exist`, id)
+		return nil, err
 	}
 
-	return ctx.GetStub().DelState(strconv.Itoa(id))
+	return f.updateBatch(ctx, items, s.maxBatchSize())
 }
 
-func (s *SmartContract) TransferBook(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
-	a, err := s.GetBook(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get book failed - %w`, err)
-	}
-
-	a.Owner = newOwner
-	aByts, err := json.Marshal(a)
+// TransferAssetsBatch transfers the records of the given kind of a JSON array of items with their id,
+// newOwner and optionally their expected version, either all of them or none
+func (s *SmartContract) TransferAssetsBatch(ctx contractapi.TransactionContextInterface, kind string, items string) (*BatchReport, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal book failed - %w`, err)
+		return nil, err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.transferBatch(ctx, items, s.maxBatchSize())
 }
 
-func (s *SmartContract) GetAllBooks(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
-	// ranging with empty start and end keys returns all books in chaincode namespace
-	// an identical function of get all assets
-	itr, err := ctx.GetStub().GetStateByRange(``, ``)
-	if err != nil {
-		return nil, fmt.Errorf(`get book state by range faied - %w`, err)
-	}
-	defer itr.Close()
-
-	var ats []*Asset
-	for itr.HasNext() {
-		res, err := itr.Next()
-		if err != nil {
-			return nil, fmt.Errorf(`iterating next book query result failed - %w`, err)
-		}
-
-		var a Asset
-		if err = json.Unmarshal(res.Value, &a); err != nil {
-			return nil, fmt.Errorf(`unmarshal of book failed - %w`, err)
-		}
-
-		ats = append(ats, &a)
-	}
-
-	return ats, nil
+func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
+	return assetFamily.getAll(ctx, s.maxQueryResults())
 }
 
-func (s *SmartContract) BookExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
+// GetAssetsWithPagination returns a page of records of the given kind along with the bookmark of the
+// next page
+func (s *SmartContract) GetAssetsWithPagination(ctx contractapi.TransactionContextInterface, kind string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return false, fmt.Errorf(`get state failed for book %d - %w`, id, err)
+		return nil, err
 	}
 
-	return aByts != nil, nil
+	return f.page(ctx, pageSize, bookmark)
 }
 
-func (s *SmartContract) ChangeBookColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
-	a, err := s.GetBook(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
-
-	a.Color = clr
-	aByts, err := json.Marshal(a)
+// QueryAssets returns a page of records of the given kind matching a CouchDB selector, which requires
+// CouchDB as the state database
+func (s *SmartContract) QueryAssets(ctx contractapi.TransactionContextInterface, kind string, selector string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
+		return nil, err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.query(ctx, selector, pageSize, bookmark)
 }
 
-func (s *SmartContract) ChangeBookValue(ctx contractapi.TransactionContextInterface, id int, val int) error {
-	a, err := s.GetBook(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
-
-	a.Value = val
-	aByts, err := json.Marshal(a)
+// QueryAssetsByTimestamp returns a page of records of the given kind whose createdAt or updatedAt
+// timestamp lies in [from, to), given in RFC 3339 where an empty bound leaves the range open,
+// which requires CouchDB as the state database
+func (s *SmartContract) QueryAssetsByTimestamp(ctx contractapi.TransactionContextInterface, kind string, field string, from string, to string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
+		return nil, err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.queryByTimestamp(ctx, field, from, to, pageSize, bookmark)
 }
 
-// House functions
-
-func (s *SmartContract) CreateHouse(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.HouseExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`create house failed - %w`, err)
-	}
-
-	if exists {
-		return fmt.Errorf(`house with id %d already exists`, id)
-	}
-
-	house := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(house)
+func (s *SmartContract) QueryAssetsByOwner(ctx contractapi.TransactionContextInterface, kind string, owner string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`marshal house failed - %w`, err)
+		return nil, err
 	}
 
-	return ctx.GetStub().PutState(strconv.Itoa(house.ID), aByts)
+	return f.queryByOwner(ctx, owner, pageSize, bookmark)
 }
 
-func (s *SmartContract) GetHouse(ctx contractapi.TransactionContextInterface, id int) (*Asset, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
+func (s *SmartContract) QueryAssetsByColor(ctx contractapi.TransactionContextInterface, kind string, clr string, pageSize int, bookmark string) (*Page, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return nil, fmt.Errorf(`get state failed for house %d - %w`, id, err)
-	}
-
-	if aByts == nil {
-		return nil, fmt.Errorf(`house does not exist for id %d`, id)
+		return nil, err
 	}
 
-	var a Asset
-	if err = json.Unmarshal(aByts, &a); err != nil {
-		return nil, fmt.Errorf(`unmarshal house failed for house %d - %w`, id, err)
-	}
-
-	return &a, nil
+	return f.queryByColor(ctx, clr, pageSize, bookmark)
 }
 
-func (s *SmartContract) UpdateHouse(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val int) error {
-	exists, err := s.HouseExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking house existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`house with id %d does not exist`, id)
-	}
-
-	a := Asset{
-		Color: color,
-		ID:    id,
-		Owner: owner,
-		Value: val,
-	}
-
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal house failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(a.ID), aByts)
+// GetAssetsByOwner returns the assets of an owner from the owner index, which also works on LevelDB
+func (s *SmartContract) GetAssetsByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Asset, error) {
+	return assetFamily.byOwner(ctx, owner, s.maxQueryResults())
 }
 
-func (s *SmartContract) DeleteHouse(ctx contractapi.TransactionContextInterface, id int) error {
-	exists, err := s.HouseExists(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`checking house existence failed - %w`, err)
-	}
-
-	if !exists {
-		return fmt.Errorf(`house with id %d does not exist`, id)
-	}
-
-	return ctx.GetStub().DelState(strconv.Itoa(id))
+func (s *SmartContract) GetAssetsByColor(ctx contractapi.TransactionContextInterface, clr string) ([]*Asset, error) {
+	return assetFamily.byColor(ctx, clr, s.maxQueryResults())
 }
 
-func (s *SmartContract) TransferHouse(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
-	a, err := s.GetHouse(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get house failed - %w`, err)
-	}
-
-	a.Owner = newOwner
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal house failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	return assetFamily.exists(ctx, id)
 }
 
-func (s *SmartContract) GetAllHouses(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
-	// ranging with empty start and end keys returns all houses in chaincode namespace
-	// an identical function of get all assets
-	itr, err := ctx.GetStub().GetStateByRange(``, ``)
-	if err != nil {
-		return nil, fmt.Errorf(`get house state by range faied - %w`, err)
-	}
-	defer itr.Close()
-
-	var ats []*Asset
-	for itr.HasNext() {
-		res, err := itr.Next()
-		if err != nil {
-			return nil, fmt.Errorf(`iterating next house query result failed - %w`, err)
-		}
-
-		var a Asset
-		if err = json.Unmarshal(res.Value, &a); err != nil {
-			return nil, fmt.Errorf(`unmarshal of house failed - %w`, err)
-		}
-
-		ats = append(ats, &a)
-	}
-
-	return ats, nil
+func (s *SmartContract) ChangeAssetColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
+	return assetFamily.changeColour(ctx, id, clr)
 }
 
-func (s *SmartContract) HouseExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
-	aByts, err := ctx.GetStub().GetState(strconv.Itoa(id))
-	if err != nil {
-		return false, fmt.Errorf(`get state failed for house %d - %w`, id, err)
-	}
-
-	return aByts != nil, nil
+func (s *SmartContract) ChangeAssetValue(ctx contractapi.TransactionContextInterface, id int, val string) error {
+	return assetFamily.changeValue(ctx, id, val)
 }
 
-func (s *SmartContract) ChangeHouseColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
-	a, err := s.GetHouse(ctx, id)
+// GetAssetHistory returns a page of all past versions of the record of the given kind, starting
+// after the bookmark
+func (s *SmartContract) GetAssetHistory(ctx contractapi.TransactionContextInterface, kind string, id int, pageSize int, bookmark string) (*HistoryPage, error) {
+	f, err := familyOf(kind)
 	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
+		return nil, err
 	}
 
-	a.Color = clr
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
+	return f.history(ctx, id, pageSize, bookmark)
 }
 
-func (s *SmartContract) ChangeHouseValue(ctx contractapi.TransactionContextInterface, id int, val int) error {
-	a, err := s.GetHouse(ctx, id)
-	if err != nil {
-		return fmt.Errorf(`get asset failed - %w`, err)
-	}
+// check has nothing to add, as assets only carry the common attributes
+func (a *Asset) check(v *validator) {}
 
-	a.Value = val
-	aByts, err := json.Marshal(a)
-	if err != nil {
-		return fmt.Errorf(`marshal asset failed - %w`, err)
-	}
-
-	return ctx.GetStub().PutState(strconv.Itoa(id), aByts)
-}
+func (a *Asset) id() int                  { return a.ID }
+func (a *Asset) setID(id int)             { a.ID = id }
+func (a *Asset) owner() string            { return a.Owner }
+func (a *Asset) setOwner(owner string)    { a.Owner = owner }
+func (a *Asset) color() string            { return a.Color }
+func (a *Asset) setColor(clr string)      { a.Color = clr }
+func (a *Asset) value() Money             { return a.Value }
+func (a *Asset) setValue(val Money)       { a.Value = val }
+func (a *Asset) setCreated(at, by string) { a.CreatedAt, a.CreatedBy = at, by }
+func (a *Asset) setUpdated(at, by string) { a.UpdatedAt, a.UpdatedBy = at, by }
+func (a *Asset) version() int             { return a.Version }
+func (a *Asset) setVersion(ver int)       { a.Version = ver }
+func (a *Asset) setDocType(kind string)   { a.DocType = kind }
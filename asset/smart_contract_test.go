@@ -1,13 +1,13 @@
 package asset
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/tryfix/log"
+	"reflect"
 	"strconv"
 	"testing"
 )
@@ -21,11 +21,11 @@ const (
 )
 
 var (
-	testAsset = Asset{Color: "brown", ID: 88, Owner: "Arnold", Value: 989}
+	testAsset = Asset{Color: "brown", DocType: kindAsset, ID: 88, Owner: "Arnold", Value: euros(989)}
 )
 
 func newMockStub() *shimtest.MockStub {
-	sc := SmartContract{}
+	sc := SmartContract{AdminMSPs: []string{testMSP}}
 	assetCC, err := contractapi.NewChaincode(&sc)
 	if err != nil {
 		log.Fatal("error creating asset chaincode: ", err)
@@ -35,6 +35,8 @@ func newMockStub() *shimtest.MockStub {
 	if stub == nil {
 		log.Fatal("failed to create mock stub")
 	}
+	// admins may create records on behalf of the owners used by the fixtures
+	stub.Creator = adminIdentity
 
 	return stub
 }
@@ -46,7 +48,7 @@ func TestSmartContractCreateAsset(t *testing.T) {
 	in := marshalAsset()
 	out := getState(stub, testAsset.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -56,13 +58,14 @@ func TestSmartContractUpdateAsset(t *testing.T) {
 	testCreate(stub, t)
 
 	testAsset.Color = clrBlue
-	testAsset.Value = 1500
+	testAsset.Value = euros(1500)
 	testUpdate(stub, t)
+	testAsset.Version = 2
 
 	in := marshalAsset()
 	out := getState(stub, testAsset.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -77,7 +80,7 @@ func TestSmartContractGetAsset(t *testing.T) {
 	}
 
 	in := marshalAsset()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -92,11 +95,20 @@ func TestSmartContractGetAllAssets(t *testing.T) {
 	}
 
 	in := marshalAssets()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
 
+func TestSmartContractInitLedgerTwice(t *testing.T) {
+	stub := newMockStub()
+	testInitLedger(stub, t)
+
+	if res := stub.MockInvoke(`4`, [][]byte{[]byte("InitLedger")}); DecodeError(res.Message).Code != CodeAlreadyExists {
+		t.Fatalf(errExpect, CodeAlreadyExists, res.Message)
+	}
+}
+
 func TestSmartContractDeleteAsset(t *testing.T) {
 	stub := newMockStub()
 	testCreate(stub, t)
@@ -124,9 +136,10 @@ func TestSmartContractTransferAsset(t *testing.T) {
 	}
 
 	testAsset.Owner = ownrDavid
+	testAsset.Version = 2
 	out := getState(stub, testAsset.ID, t)
 	in := marshalAsset()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -142,9 +155,10 @@ func TestSmartContractChangeAssetColour(t *testing.T) {
 	}
 
 	testAsset.Color = clrBrown
+	testAsset.Version = 2
 	out := getState(stub, testAsset.ID, t)
 	in := marshalAsset()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -159,10 +173,11 @@ func TestSmartContractChangeAssetValue(t *testing.T) {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
-	testAsset.Value = 888
+	testAsset.Value = euros(888)
+	testAsset.Version = 2
 	out := getState(stub, testAsset.ID, t)
 	in := marshalAsset()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -175,22 +190,25 @@ func testInitLedger(stub *shimtest.MockStub, t *testing.T) {
 
 func testCreate(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`4`, [][]byte{
-		[]byte("CreateAsset"), []byte(testAsset.Color), []byte(strconv.Itoa(testAsset.ID)), []byte(testAsset.Owner), []byte(strconv.Itoa(testAsset.Value)),
+		[]byte("CreateAsset"), []byte(testAsset.Color), []byte(strconv.Itoa(testAsset.ID)), []byte(testAsset.Owner), []byte(testAsset.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
+
+	// a created record starts at version 1
+	testAsset.Version = 1
 }
 
 func testUpdate(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`5`, [][]byte{
-		[]byte("UpdateAsset"), []byte(testAsset.Color), []byte(strconv.Itoa(testAsset.ID)), []byte(testAsset.Owner), []byte(strconv.Itoa(testAsset.Value)),
+		[]byte("UpdateAsset"), []byte(testAsset.Color), []byte(strconv.Itoa(testAsset.ID)), []byte(testAsset.Owner), []byte(testAsset.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 }
 
 func getState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
-	out, err := stub.GetState(strconv.Itoa(id))
+	out, err := stub.GetState(compositeKey(kindAsset, id, t))
 	if err != nil {
 		t.Fatalf("failed to retrieve asset info - %s", err.Error())
 	}
@@ -198,6 +216,15 @@ func getState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
 	return out
 }
 
+func compositeKey(kind string, id int, t *testing.T) string {
+	k, err := shim.CreateCompositeKey(kind, []string{strconv.Itoa(id)})
+	if err != nil {
+		t.Fatalf("failed to create composite key - %s", err.Error())
+	}
+
+	return k
+}
+
 func marshalAsset() []byte {
 	byts, err := json.Marshal(testAsset)
 	if err != nil {
@@ -215,3 +242,37 @@ func marshalAssets() []byte {
 
 	return byts
 }
+
+// sameRecords compares the JSON encoding of records apart from their audit stamps, as
+// the mock stub stamps each transaction with the current time
+func sameRecords(in, out []byte, t *testing.T) bool {
+	var want, got interface{}
+	if err := json.Unmarshal(in, &want); err != nil {
+		t.Fatalf("failed to unmarshal expected records - %s", err.Error())
+	}
+
+	if err := json.Unmarshal(out, &got); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(unstamped(want), unstamped(got))
+}
+
+func unstamped(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			unstamped(e)
+		}
+	case map[string]interface{}:
+		for _, field := range auditFields {
+			delete(v, field)
+		}
+
+		for _, e := range v {
+			unstamped(e)
+		}
+	}
+
+	return v
+}
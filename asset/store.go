@@ -0,0 +1,118 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// record is the set of accessors the generic store requires from the pointer type of an asset kind
+type record[T any] interface {
+	*T
+	id() int
+	setID(id int)
+	owner() string
+	setOwner(owner string)
+	color() string
+	setColor(clr string)
+	value() Money
+	setValue(val Money)
+	setCreated(at, by string)
+	setUpdated(at, by string)
+	version() int
+	setVersion(ver int)
+	setDocType(kind string)
+	// check adds the violations of the rules of the kind
+	check(v *validator)
+}
+
+// store persists the records of a single asset kind under the composite key namespace of the kind
+type store[T any, P record[T]] struct {
+	kind string
+}
+
+func (s store[T, P]) exists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	byts, err := readState(ctx, s.kind, id)
+	if err != nil {
+		return false, fmt.Errorf(`get state failed for %s %d - %w`, s.kind, id, err)
+	}
+
+	return byts != nil, nil
+}
+
+func (s store[T, P]) get(ctx contractapi.TransactionContextInterface, id int) (*T, error) {
+	r, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r == nil {
+		return nil, errorf(CodeNotFound, `%s with id %d does not exist`, s.kind, id)
+	}
+
+	return r, nil
+}
+
+// find returns the record of the given id or nil if it does not exist
+func (s store[T, P]) find(ctx contractapi.TransactionContextInterface, id int) (*T, error) {
+	byts, err := readState(ctx, s.kind, id)
+	if err != nil {
+		return nil, fmt.Errorf(`get state failed for %s %d - %w`, s.kind, id, err)
+	}
+
+	if byts == nil {
+		return nil, nil
+	}
+
+	var r T
+	if err = json.Unmarshal(byts, &r); err != nil {
+		return nil, fmt.Errorf(`unmarshal %s failed for %s %d - %w`, s.kind, s.kind, id, err)
+	}
+
+	return &r, nil
+}
+
+// put writes the record under its composite key along with its secondary index entries,
+// and tags it with the kind which rich queries and CouchDB indexes use to tell kinds apart
+func (s store[T, P]) put(ctx contractapi.TransactionContextInterface, r *T) error {
+	P(r).setDocType(s.kind)
+	prev, err := s.find(ctx, P(r).id())
+	if err != nil {
+		return err
+	}
+
+	if err = s.reindex(ctx, prev, r); err != nil {
+		return err
+	}
+
+	byts, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf(`marshal %s failed - %w`, s.kind, err)
+	}
+
+	return writeState(ctx, s.kind, P(r).id(), byts)
+}
+
+func (s store[T, P]) del(ctx contractapi.TransactionContextInterface, id int) error {
+	prev, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.reindex(ctx, prev, nil); err != nil {
+		return err
+	}
+
+	return deleteState(ctx, s.kind, id)
+}
+
+func (s store[T, P]) all(ctx contractapi.TransactionContextInterface, limit int) ([]*T, error) {
+	// an empty partial key returns all records in the composite key namespace of the kind
+	itr, err := ctx.GetStub().GetStateByPartialCompositeKey(s.kind, []string{})
+	if err != nil {
+		return nil, fmt.Errorf(`get %s state by partial composite key failed - %w`, s.kind, err)
+	}
+	defer itr.Close()
+
+	return s.decode(itr, limit)
+}
@@ -0,0 +1,218 @@
+package asset
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/tryfix/log"
+)
+
+// queryStub extends the mock stub with the ledger queries which are not implemented
+// by shimtest, and invokes the chaincode with itself as the stub
+type queryStub struct {
+	*shimtest.MockStub
+	cc      *contractapi.ContractChaincode
+	args    []string
+	history map[string][]*queryresult.KeyModification
+	// now overrides the transaction timestamp if set
+	now *timestamp.Timestamp
+}
+
+func newQueryStub() *queryStub {
+	cc, err := contractapi.NewChaincode(&SmartContract{AdminMSPs: []string{testMSP}})
+	if err != nil {
+		log.Fatal("error creating asset chaincode: ", err)
+	}
+
+	stub := &queryStub{
+		MockStub: shimtest.NewMockStub("queryStub", cc),
+		cc:       cc,
+		history:  make(map[string][]*queryresult.KeyModification),
+	}
+	stub.Creator = adminIdentity
+
+	return stub
+}
+
+func (s *queryStub) invoke(txID string, args ...string) pb.Response {
+	s.args = args
+	s.MockTransactionStart(txID)
+	defer s.MockTransactionEnd(txID)
+
+	return s.cc.Invoke(s)
+}
+
+func (s *queryStub) GetFunctionAndParameters() (string, []string) {
+	return s.args[0], s.args[1:]
+}
+
+func (s *queryStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
+	if s.now != nil {
+		return s.now, nil
+	}
+
+	return s.MockStub.GetTxTimestamp()
+}
+
+// GetPrivateDataHash returns the hash of the private data, which other orgs also see on their peers
+func (s *queryStub) GetPrivateDataHash(collection, key string) ([]byte, error) {
+	byts, err := s.GetPrivateData(collection, key)
+	if err != nil || byts == nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(byts)
+	return sum[:], nil
+}
+
+func (s *queryStub) DelPrivateData(collection, key string) error {
+	delete(s.PvtState[collection], key)
+	return nil
+}
+
+func (s *queryStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return &historyIterator{mods: s.history[key]}, nil
+}
+
+// GetStateByPartialCompositeKeyWithPagination emulates peer pagination where the bookmark
+// is the first key of the next page
+func (s *queryStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	itr, err := s.GetStateByPartialCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return paginate(itr, pageSize, bookmark, func(*queryresult.KV) bool { return true })
+}
+
+// GetQueryResultWithPagination emulates CouchDB selectors composed of field equalities,
+// string comparisons and $and
+func (s *queryStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (shim.StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	var q struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(query), &q); err != nil {
+		return nil, nil, err
+	}
+
+	return paginate(shimtest.NewMockStateRangeQueryIterator(s.MockStub, ``, ``), pageSize, bookmark, func(kv *queryresult.KV) bool {
+		var doc map[string]interface{}
+		return json.Unmarshal(kv.Value, &doc) == nil && matches(q.Selector, doc)
+	})
+}
+
+func matches(selector, doc map[string]interface{}) bool {
+	for field, val := range selector {
+		if ops, ok := val.(map[string]interface{}); ok {
+			if !compares(ops, doc[field]) {
+				return false
+			}
+			continue
+		}
+
+		if field != `$and` {
+			if doc[field] != val {
+				return false
+			}
+			continue
+		}
+
+		for _, sub := range val.([]interface{}) {
+			if !matches(sub.(map[string]interface{}), doc) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// compares applies the comparison operators of a field on its string value, where
+// {"$gt": null} matches any value
+func compares(ops map[string]interface{}, val interface{}) bool {
+	s, ok := val.(string)
+	for op, bound := range ops {
+		b, _ := bound.(string)
+		switch {
+		case !ok:
+			return false
+		case op == `$gt` && bound == nil:
+		case op == `$gt` && s <= b, op == `$gte` && s < b, op == `$lt` && s >= b, op == `$lte` && s > b:
+			return false
+		}
+	}
+
+	return true
+}
+
+func paginate(itr shim.StateQueryIteratorInterface, pageSize int32, bookmark string,
+	match func(kv *queryresult.KV) bool) (shim.StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	defer itr.Close()
+
+	page := &kvIterator{}
+	meta := &pb.QueryResponseMetadata{}
+	for itr.HasNext() {
+		kv, err := itr.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if kv.Key < bookmark || !match(kv) {
+			continue
+		}
+
+		if len(page.kvs) == int(pageSize) {
+			meta.Bookmark = kv.Key
+			break
+		}
+		page.kvs = append(page.kvs, kv)
+	}
+
+	meta.FetchedRecordsCount = int32(len(page.kvs))
+	return page, meta, nil
+}
+
+type kvIterator struct {
+	kvs []*queryresult.KV
+}
+
+func (i *kvIterator) HasNext() bool {
+	return len(i.kvs) > 0
+}
+
+func (i *kvIterator) Next() (*queryresult.KV, error) {
+	kv := i.kvs[0]
+	i.kvs = i.kvs[1:]
+
+	return kv, nil
+}
+
+func (i *kvIterator) Close() error {
+	return nil
+}
+
+type historyIterator struct {
+	mods []*queryresult.KeyModification
+}
+
+func (i *historyIterator) HasNext() bool {
+	return len(i.mods) > 0
+}
+
+func (i *historyIterator) Next() (*queryresult.KeyModification, error) {
+	mod := i.mods[0]
+	i.mods = i.mods[1:]
+
+	return mod, nil
+}
+
+func (i *historyIterator) Close() error {
+	return nil
+}
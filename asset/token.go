@@ -0,0 +1,347 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"math"
+	"strconv"
+)
+
+// names of the events of the token contract as defined by ERC-20
+const (
+	EventTransfer = `Transfer`
+	EventApproval = `Approval`
+)
+
+// object types of the token keys. Balances and the supply are kept as deltas keyed by
+// the transaction which wrote them instead of a single key per account, so that
+// concurrent credits of an account do not conflict. The supply, which only the minter
+// writes, is compacted into a single delta by every mint and burn.
+const (
+	balanceKey   = `balance~account~tx`
+	supplyKey    = `supply~tx`
+	allowanceKey = `allowance~owner~spender`
+)
+
+// TokenContract is an ERC-20 style fungible token used to settle asset purchases,
+// whose accounts are client identities recorded in the same form as asset owners
+type TokenContract struct {
+	contractapi.Contract
+	// MinterMSP is the only org allowed to mint and burn tokens, where none disables both
+	MinterMSP string
+}
+
+// TransferEvent is the payload of EventTransfer, where From is empty for minted and
+// To is empty for burnt tokens
+type TransferEvent struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value int    `json:"value"`
+}
+
+// ApprovalEvent is the payload of EventApproval
+type ApprovalEvent struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	Value   int    `json:"value"`
+}
+
+// Mint creates tokens on the account of the invoker, who must belong to the minter org
+func (t *TokenContract) Mint(ctx contractapi.TransactionContextInterface, amount int) error {
+	minter, err := t.minter(ctx)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return errorf(CodeInvalid, `mint amount %d must be positive`, amount)
+	}
+
+	// balances add up to the supply, hence none of them overflows unless the supply does
+	if err = putSupply(ctx, amount); err != nil {
+		return err
+	}
+
+	if err = credit(ctx, minter, amount); err != nil {
+		return err
+	}
+
+	return setEvent(ctx, EventTransfer, TransferEvent{To: minter, Value: amount})
+}
+
+// Burn destroys tokens of the account of the invoker, who must belong to the minter org
+func (t *TokenContract) Burn(ctx contractapi.TransactionContextInterface, amount int) error {
+	minter, err := t.minter(ctx)
+	if err != nil {
+		return err
+	}
+
+	if amount <= 0 {
+		return errorf(CodeInvalid, `burn amount %d must be positive`, amount)
+	}
+
+	if err = debit(ctx, minter, amount); err != nil {
+		return err
+	}
+
+	if err = putSupply(ctx, -amount); err != nil {
+		return err
+	}
+
+	return setEvent(ctx, EventTransfer, TransferEvent{From: minter, Value: amount})
+}
+
+// Transfer moves tokens from the account of the invoker to the recipient
+func (t *TokenContract) Transfer(ctx contractapi.TransactionContextInterface, recipient string, amount int) error {
+	from, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	return move(ctx, from, recipient, amount)
+}
+
+// BalanceOf returns the number of tokens of an account
+func (t *TokenContract) BalanceOf(ctx contractapi.TransactionContextInterface, account string) (int, error) {
+	return sumDeltas(ctx, balanceKey, []string{account}, nil)
+}
+
+// ClientAccountID returns the account of the invoker
+func (t *TokenContract) ClientAccountID(ctx contractapi.TransactionContextInterface) (string, error) {
+	return invoker(ctx)
+}
+
+// TotalSupply returns the number of tokens minted and not burnt
+func (t *TokenContract) TotalSupply(ctx contractapi.TransactionContextInterface) (int, error) {
+	return sumDeltas(ctx, supplyKey, []string{}, nil)
+}
+
+// Approve allows the spender to transfer up to value tokens of the invoker, replacing
+// any previous allowance
+func (t *TokenContract) Approve(ctx contractapi.TransactionContextInterface, spender string, value int) error {
+	owner, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	if value < 0 {
+		return errorf(CodeInvalid, `allowance %d must not be negative`, value)
+	}
+
+	if err = putAllowance(ctx, owner, spender, value); err != nil {
+		return err
+	}
+
+	return setEvent(ctx, EventApproval, ApprovalEvent{Owner: owner, Spender: spender, Value: value})
+}
+
+// Allowance returns the number of tokens of the owner the spender may still transfer
+func (t *TokenContract) Allowance(ctx contractapi.TransactionContextInterface, owner string, spender string) (int, error) {
+	k, err := ctx.GetStub().CreateCompositeKey(allowanceKey, []string{owner, spender})
+	if err != nil {
+		return 0, fmt.Errorf(`create allowance key failed - %w`, err)
+	}
+
+	byts, err := ctx.GetStub().GetState(k)
+	if err != nil {
+		return 0, fmt.Errorf(`get allowance failed - %w`, err)
+	}
+
+	if byts == nil {
+		return 0, nil
+	}
+
+	val, err := strconv.Atoi(string(byts))
+	if err != nil {
+		return 0, fmt.Errorf(`invalid allowance of %s for %s - %w`, owner, spender, err)
+	}
+
+	return val, nil
+}
+
+// TransferFrom moves tokens from an account to the recipient on behalf of its owner,
+// consuming the allowance of the invoker
+func (t *TokenContract) TransferFrom(ctx contractapi.TransactionContextInterface, from string, recipient string, amount int) error {
+	spender, err := invoker(ctx)
+	if err != nil {
+		return err
+	}
+
+	allowance, err := t.Allowance(ctx, from, spender)
+	if err != nil {
+		return err
+	}
+
+	if allowance < amount {
+		return errorf(CodeConflict, `allowance %d of %s is insufficient to transfer %d`, allowance, spender, amount)
+	}
+
+	if err = putAllowance(ctx, from, spender, allowance-amount); err != nil {
+		return err
+	}
+
+	return move(ctx, from, recipient, amount)
+}
+
+// minter returns the account of the invoker given that it belongs to the minter org
+func (t *TokenContract) minter(ctx contractapi.TransactionContextInterface) (string, error) {
+	account, err := invoker(ctx)
+	if err != nil {
+		return ``, err
+	}
+
+	if t.MinterMSP == `` || ownerMSP(account) != t.MinterMSP {
+		return ``, errorf(CodeForbidden, `only clients of the minter org can mint or burn tokens`)
+	}
+
+	return account, nil
+}
+
+func move(ctx contractapi.TransactionContextInterface, from, to string, amount int) error {
+	if amount <= 0 {
+		return errorf(CodeInvalid, `transfer amount %d must be positive`, amount)
+	}
+
+	if to == `` {
+		return errorf(CodeInvalid, `recipient of the transfer is required`)
+	}
+
+	if from == to {
+		// the balance is still checked, while the deltas of a transaction would collide
+		balance, err := sumDeltas(ctx, balanceKey, []string{from}, nil)
+		if err != nil {
+			return err
+		}
+
+		if balance < amount {
+			return errorf(CodeConflict, `balance %d of %s is insufficient to transfer %d`, balance, from, amount)
+		}
+	} else {
+		if err := debit(ctx, from, amount); err != nil {
+			return err
+		}
+
+		if err := credit(ctx, to, amount); err != nil {
+			return err
+		}
+	}
+
+	return setEvent(ctx, EventTransfer, TransferEvent{From: from, To: to, Value: amount})
+}
+
+// credit adds a delta to the account without reading its balance
+func credit(ctx contractapi.TransactionContextInterface, account string, amount int) error {
+	return putDelta(ctx, balanceKey, []string{account, ctx.GetStub().GetTxID()}, amount)
+}
+
+// debit checks the balance of the account and compacts its deltas into a single one
+// holding the remaining balance
+func debit(ctx contractapi.TransactionContextInterface, account string, amount int) error {
+	var keys []string
+	balance, err := sumDeltas(ctx, balanceKey, []string{account}, func(k string) { keys = append(keys, k) })
+	if err != nil {
+		return err
+	}
+
+	if balance < amount {
+		return errorf(CodeConflict, `balance %d of %s is insufficient to transfer %d`, balance, account, amount)
+	}
+
+	for _, k := range keys {
+		if err = ctx.GetStub().DelState(k); err != nil {
+			return fmt.Errorf(`delete balance of %s failed - %w`, account, err)
+		}
+	}
+
+	if balance == amount {
+		return nil
+	}
+
+	return credit(ctx, account, balance-amount)
+}
+
+// putSupply adds a delta to the total supply, compacting its deltas into a single one the
+// way debit compacts balances. Only the minter org writes the supply, so that mints and burns
+// read one delta instead of every delta ever written.
+func putSupply(ctx contractapi.TransactionContextInterface, delta int) error {
+	var keys []string
+	supply, err := sumDeltas(ctx, supplyKey, []string{}, func(k string) { keys = append(keys, k) })
+	if err != nil {
+		return err
+	}
+
+	if delta > 0 && supply > math.MaxInt-delta {
+		return errorf(CodeConflict, `minting %d would overflow the total supply %d`, delta, supply)
+	}
+
+	for _, k := range keys {
+		if err = ctx.GetStub().DelState(k); err != nil {
+			return fmt.Errorf(`delete supply failed - %w`, err)
+		}
+	}
+
+	if supply+delta == 0 {
+		return nil
+	}
+
+	return putDelta(ctx, supplyKey, []string{ctx.GetStub().GetTxID()}, supply+delta)
+}
+
+func putDelta(ctx contractapi.TransactionContextInterface, objectType string, attrs []string, delta int) error {
+	k, err := ctx.GetStub().CreateCompositeKey(objectType, attrs)
+	if err != nil {
+		return fmt.Errorf(`create %s key failed - %w`, objectType, err)
+	}
+
+	if err = ctx.GetStub().PutState(k, []byte(strconv.Itoa(delta))); err != nil {
+		return fmt.Errorf(`put %s failed - %w`, objectType, err)
+	}
+
+	return nil
+}
+
+// sumDeltas adds up the deltas under a partial key, passing each of their keys to visit if given
+func sumDeltas(ctx contractapi.TransactionContextInterface, objectType string, attrs []string, visit func(k string)) (int, error) {
+	itr, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, attrs)
+	if err != nil {
+		return 0, fmt.Errorf(`get %s state by partial composite key failed - %w`, objectType, err)
+	}
+	defer itr.Close()
+
+	sum := 0
+	for itr.HasNext() {
+		res, err := itr.Next()
+		if err != nil {
+			return 0, fmt.Errorf(`iterating next %s failed - %w`, objectType, err)
+		}
+
+		delta, err := strconv.Atoi(string(res.Value))
+		if err != nil {
+			return 0, fmt.Errorf(`invalid %s delta - %w`, objectType, err)
+		}
+
+		if (delta > 0 && sum > math.MaxInt-delta) || (delta < 0 && sum < math.MinInt-delta) {
+			return 0, fmt.Errorf(`%s overflows`, objectType)
+		}
+		sum += delta
+
+		if visit != nil {
+			visit(res.Key)
+		}
+	}
+
+	return sum, nil
+}
+
+func putAllowance(ctx contractapi.TransactionContextInterface, owner, spender string, value int) error {
+	k, err := ctx.GetStub().CreateCompositeKey(allowanceKey, []string{owner, spender})
+	if err != nil {
+		return fmt.Errorf(`create allowance key failed - %w`, err)
+	}
+
+	if err = ctx.GetStub().PutState(k, []byte(strconv.Itoa(value))); err != nil {
+		return fmt.Errorf(`put allowance failed - %w`, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func newTokenStub(t *testing.T) *shimtest.MockStub {
+	cc, err := contractapi.NewChaincode(&SmartContract{AdminMSPs: []string{testMSP}}, &TokenContract{MinterMSP: testMSP})
+	if err != nil {
+		t.Fatalf("error creating chaincode - %s", err.Error())
+	}
+
+	return shimtest.NewMockStub("tokenStub", cc)
+}
+
+// tokenTxs numbers the token transactions, as balances are keyed by transaction id
+var tokenTxs int
+
+func invokeToken(stub *shimtest.MockStub, creator []byte, args ...string) ([]byte, string, bool) {
+	tokenTxs++
+	stub.Creator = creator
+	args[0] = "TokenContract:" + args[0]
+	res := stub.MockInvoke(strconv.Itoa(tokenTxs), toArgs(args))
+
+	return res.Payload, res.Message, res.Status == shim.OK
+}
+
+func testTokenQuery(stub *shimtest.MockStub, in string, t *testing.T, args ...string) {
+	out, msg, ok := invokeToken(stub, aliceIdentity, args...)
+	if !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+
+	if string(out) != in {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func testTokenInvoke(stub *shimtest.MockStub, creator []byte, t *testing.T, args ...string) {
+	if _, msg, ok := invokeToken(stub, creator, args...); !ok {
+		t.Fatalf(errOK, shim.ERROR, msg)
+	}
+}
+
+func TestTokenContractMint(t *testing.T) {
+	stub := newTokenStub(t)
+	if _, _, ok := invokeToken(stub, carolIdentity, "Mint", "1000"); ok {
+		t.Fatalf(`clients of other orgs than the minter org should not mint`)
+	}
+
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", "1000")
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", "500")
+	testTokenInvoke(stub, aliceIdentity, t, "Burn", "300")
+
+	testTokenQuery(stub, `1200`, t, "TotalSupply")
+	stub.Creator = aliceIdentity
+	testTokenQuery(stub, `1200`, t, "BalanceOf", ownerOf(stub, t))
+
+	// mints and burns compact the supply instead of piling up its deltas
+	stub.MockTransactionStart(`supply`)
+	defer stub.MockTransactionEnd(`supply`)
+	itr, err := stub.GetStateByPartialCompositeKey(supplyKey, []string{})
+	if err != nil {
+		t.Fatalf("failed to get supply - %s", err.Error())
+	}
+	defer itr.Close()
+
+	deltas := 0
+	for ; itr.HasNext(); deltas++ {
+		if _, err = itr.Next(); err != nil {
+			t.Fatalf("failed to iterate supply - %s", err.Error())
+		}
+	}
+
+	if deltas != 1 {
+		t.Fatalf(`expected a single supply delta, got %d`, deltas)
+	}
+}
+
+func TestTokenContractMintOverflow(t *testing.T) {
+	stub := newTokenStub(t)
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", strconv.Itoa(math.MaxInt))
+
+	_, msg, ok := invokeToken(stub, aliceIdentity, "Mint", "1")
+	if ok || DecodeError(msg).Code != CodeConflict {
+		t.Fatalf(errExpect, CodeConflict, msg)
+	}
+
+	// minting on another account would still overflow the supply
+	if _, _, ok = invokeToken(stub, newIdentity(testMSP, "dave", ""), "Mint", "1"); ok {
+		t.Fatalf(`minting beyond the maximum supply should fail`)
+	}
+
+	testTokenQuery(stub, strconv.Itoa(math.MaxInt), t, "TotalSupply")
+	stub.Creator = aliceIdentity
+	testTokenQuery(stub, strconv.Itoa(math.MaxInt), t, "BalanceOf", ownerOf(stub, t))
+}
+
+func TestTokenContractTransfer(t *testing.T) {
+	stub := newTokenStub(t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub, t)
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub, t)
+
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", "1000")
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", "1000")
+	testTokenInvoke(stub, aliceIdentity, t, "Transfer", bob, "300")
+	if _, _, ok := invokeToken(stub, bobIdentity, "Transfer", alice, "301"); ok {
+		t.Fatalf(`transferring more than the balance should fail`)
+	}
+
+	testTokenQuery(stub, `1700`, t, "BalanceOf", alice)
+	testTokenQuery(stub, `300`, t, "BalanceOf", bob)
+
+	// the debit compacts the deltas of the account
+	stub.MockTransactionStart(`deltas`)
+	itr, err := stub.GetStateByPartialCompositeKey(balanceKey, []string{alice})
+	if err != nil {
+		t.Fatalf("failed to get balance deltas - %s", err.Error())
+	}
+
+	for n := 0; itr.HasNext(); n++ {
+		if _, err = itr.Next(); err != nil || n > 0 {
+			t.Fatalf(`expected a single balance delta of %s`, alice)
+		}
+	}
+	stub.MockTransactionEnd(`deltas`)
+}
+
+func TestTokenContractTransferFrom(t *testing.T) {
+	stub := newTokenStub(t)
+	stub.Creator = aliceIdentity
+	alice := ownerOf(stub, t)
+	stub.Creator = bobIdentity
+	bob := ownerOf(stub, t)
+
+	testTokenInvoke(stub, aliceIdentity, t, "Mint", "1000")
+	testTokenInvoke(stub, aliceIdentity, t, "Approve", bob, "100")
+	testTokenInvoke(stub, bobIdentity, t, "TransferFrom", alice, bob, "80")
+	if _, _, ok := invokeToken(stub, bobIdentity, "TransferFrom", alice, bob, "21"); ok {
+		t.Fatalf(`transferring more than the allowance should fail`)
+	}
+
+	testTokenQuery(stub, `20`, t, "Allowance", alice, bob)
+	testTokenQuery(stub, `920`, t, "BalanceOf", alice)
+	testTokenQuery(stub, `80`, t, "BalanceOf", bob)
+}
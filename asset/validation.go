@@ -0,0 +1,119 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// length limits of the free text attributes of records
+const (
+	maxOwnerLength = 1024
+	maxTextLength  = 256
+)
+
+// colours is the vocabulary of colours records may take, compared regardless of case
+var colours = map[string]bool{
+	`beige`: true, `black`: true, `blue`: true, `brown`: true, `gold`: true, `green`: true,
+	`grey`: true, `orange`: true, `pink`: true, `purple`: true, `red`: true, `silver`: true,
+	`white`: true, `yellow`: true,
+}
+
+// Violation is an attribute of a record failing validation along with the reason
+type Violation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError lists every violation of a record rejected before it is written. It is
+// an error of CodeInvalid, whose message is the JSON encoding of the error.
+type ValidationError struct {
+	ID         int         `json:"id"`
+	Kind       string      `json:"kind"`
+	Violations []Violation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	byts, err := json.Marshal(e)
+	if err != nil {
+		return (&Error{Code: CodeInvalid, Message: fmt.Sprintf(`invalid %s %d`, e.Kind, e.ID)}).Error()
+	}
+
+	return (&Error{Code: CodeInvalid, Message: string(byts)}).Error()
+}
+
+// Is matches ErrInvalid
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// validator collects the violations of a record instead of stopping at the first one
+type validator struct {
+	violations []Violation
+}
+
+// check records a violation of the field unless ok holds
+func (v *validator) check(ok bool, field string, format string, args ...interface{}) {
+	if !ok {
+		v.violations = append(v.violations, Violation{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+}
+
+// text checks a required free text attribute against the length limit
+func (v *validator) text(field string, s string, limit int) {
+	v.check(strings.TrimSpace(s) != ``, field, `%s is required`, field)
+	v.check(len(s) <= limit, field, `%s exceeds %d characters`, field, limit)
+}
+
+// err returns the violations as a ValidationError ordered by field, or nil if there are none
+func (v *validator) err(kind string, id int) error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(v.violations, func(i, j int) bool { return v.violations[i].Field < v.violations[j].Field })
+	return &ValidationError{ID: id, Kind: kind, Violations: v.violations}
+}
+
+// violated reports whether the field has a violation already
+func (v *validator) violated(field string) bool {
+	for _, vl := range v.violations {
+		if vl.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// value parses the value argument of a record, recording a malformed one as a violation so that
+// it is reported along with the other violations of the record
+func (v *validator) value(val string) Money {
+	m, err := parseMoney(val)
+	if err != nil {
+		v.check(false, `value`, `%s`, err.Error())
+	}
+
+	return m
+}
+
+// validate checks the attributes common to all kinds followed by the rules of the kind, on top
+// of the violations of the arguments the record was built from, if any
+func (f family[T, P]) validate(r P, args ...Violation) error {
+	v := &validator{violations: args}
+	v.check(r.id() >= 0, `id`, `id %d must not be negative`, r.id())
+	v.check(colours[strings.ToLower(r.color())], `color`, `color %q is not one of the known colours`, r.color())
+	v.text(`owner`, r.owner(), maxOwnerLength)
+
+	// a malformed value argument leaves no value to check
+	if !v.violated(`value`) {
+		val := r.value()
+		_, err := minorUnits(val.Currency)
+		v.check(err == nil, `value`, `currency %q is not an ISO-4217 code`, val.Currency)
+		v.check(val.Amount >= 0, `value`, `value %s must not be negative`, val)
+	}
+
+	r.check(v)
+	return v.err(f.store.kind, r.id())
+}
@@ -0,0 +1,99 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"strings"
+	"testing"
+)
+
+// violatedFields decodes the rejection of a record and returns its failing fields
+func violatedFields(res string, t *testing.T) string {
+	e := DecodeError(res)
+	if e.Code != CodeInvalid {
+		t.Fatalf(errExpect, CodeInvalid, res)
+	}
+
+	var v ValidationError
+	if err := json.Unmarshal([]byte(e.Message), &v); err != nil {
+		t.Fatalf("failed to unmarshal validation error - %s", err.Error())
+	}
+
+	fields := make([]string, 0, len(v.Violations))
+	for _, violation := range v.Violations {
+		fields = append(fields, violation.Field)
+	}
+
+	return strings.Join(fields, `,`)
+}
+
+func TestSmartContractCreateAssetInvalid(t *testing.T) {
+	stub := newMockStub()
+
+	res := stub.MockInvoke(`1`, toArgs([]string{"CreateAsset", "", "-5", "", "-100"}))
+	if res.Status == shim.OK {
+		t.Fatalf(`asset with invalid attributes should be rejected`)
+	}
+
+	if in, out := `color,id,value`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	if res = stub.MockInvoke(`2`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "ten"})); res.Status == shim.OK {
+		t.Fatalf(`asset with a malformed value should be rejected`)
+	}
+
+	if in, out := `value`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	// a malformed value is reported along with the other violations
+	if res = stub.MockInvoke(`3`, toArgs([]string{"CreateAsset", "mauve", "-1", "", "ten"})); res.Status == shim.OK {
+		t.Fatalf(`asset with invalid attributes should be rejected`)
+	}
+
+	if in, out := `color,id,value`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+
+	if res = stub.MockInvoke(`4`, toArgs([]string{"CreateAsset", clrBlue, "5", "", "100"})); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res = stub.MockInvoke(`5`, toArgs([]string{"UpdateAsset", "mauve", "5", "", "ten"})); res.Status == shim.OK {
+		t.Fatalf(`update with invalid attributes should be rejected`)
+	}
+
+	if in, out := `color,owner,value`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractCreateVehicleWithDetailsInvalid(t *testing.T) {
+	stub := newMockStub()
+
+	res := stub.MockInvoke(`1`, toArgs([]string{
+		"CreateVehicleWithDetails", "mauve", "101", "", "30000", "VIN", "Volvo", strings.Repeat("X", maxTextLength+1), "1885", "-1",
+	}))
+	if res.Status == shim.OK {
+		t.Fatalf(`vehicle with invalid attributes should be rejected`)
+	}
+
+	if in, out := `color,mileage,model,vin,year`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractTransferAssetToNobody(t *testing.T) {
+	stub := newMockStub()
+	testCreate(stub, t)
+
+	res := stub.MockInvoke(`2`, toArgs([]string{"TransferAsset", "88", ""}))
+	if res.Status == shim.OK {
+		t.Fatalf(`transfer without an owner should be rejected`)
+	}
+
+	if in, out := `owner`, violatedFields(res.Message, t); in != out {
+		t.Fatalf(errExpect, in, out)
+	}
+}
@@ -0,0 +1,129 @@
+package asset
+
+import (
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"regexp"
+)
+
+// the first production automobile dates back to 1886
+const firstVehicleYear = 1886
+
+var (
+	vehicleFamily = newFamily[Vehicle](kindVehicle)
+
+	// vehicle identification numbers are 17 characters long and exclude the letters I, O and Q
+	vinPattern = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{17}$`)
+)
+
+// Vehicle attributes are defined in alphabetical order to make JSON struct deterministic
+type Vehicle struct {
+	Color     string `json:"color"`
+	CreatedAt string `json:"createdAt"`
+	CreatedBy string `json:"createdBy"`
+	DocType   string `json:"docType"`
+	ID        int    `json:"id"`
+	Make      string `json:"make"`
+	Mileage   int    `json:"mileage"`
+	Model     string `json:"model"`
+	Owner     string `json:"owner"`
+	UpdatedAt string `json:"updatedAt"`
+	UpdatedBy string `json:"updatedBy"`
+	Value     Money  `json:"value"`
+	Version   int    `json:"version"`
+	VIN       string `json:"vin"`
+	Year      int    `json:"year"`
+}
+
+func (s *SmartContract) CreateVehicle(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return vehicleFamily.create(ctx, color, id, owner, val)
+}
+
+func (s *SmartContract) GetVehicle(ctx contractapi.TransactionContextInterface, id int) (*Vehicle, error) {
+	return vehicleFamily.get(ctx, id)
+}
+
+func (s *SmartContract) UpdateVehicle(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string) error {
+	return vehicleFamily.update(ctx, color, id, owner, val, anyVersion)
+}
+
+// CreateVehicleWithDetails creates a vehicle along with the attributes specific to vehicles
+func (s *SmartContract) CreateVehicleWithDetails(ctx contractapi.TransactionContextInterface, color string, id int, owner string, val string, vin string, manufacturer string, model string, year int, mileage int) error {
+	args := &validator{}
+	v := &Vehicle{Color: color, ID: id, Make: manufacturer, Mileage: mileage, Model: model, Owner: owner, Value: args.value(val), VIN: vin, Year: year}
+
+	return vehicleFamily.createRecord(ctx, v, args.violations...)
+}
+
+// UpdateVehicleDetails replaces the attributes specific to vehicles of an existing vehicle
+func (s *SmartContract) UpdateVehicleDetails(ctx contractapi.TransactionContextInterface, id int, vin string, manufacturer string, model string, year int, mileage int) error {
+	return vehicleFamily.modify(ctx, id, EventAssetUpdated, func(v *Vehicle) error {
+		v.VIN = vin
+		v.Make = manufacturer
+		v.Model = model
+		v.Year = year
+		v.Mileage = mileage
+
+		return nil
+	})
+}
+
+func (s *SmartContract) DeleteVehicle(ctx contractapi.TransactionContextInterface, id int) error {
+	return vehicleFamily.delete(ctx, id)
+}
+
+func (s *SmartContract) TransferVehicle(ctx contractapi.TransactionContextInterface, id int, newOwner string) error {
+	return vehicleFamily.transfer(ctx, id, newOwner, anyVersion)
+}
+
+func (s *SmartContract) GetAllVehicles(ctx contractapi.TransactionContextInterface) ([]*Vehicle, error) {
+	return vehicleFamily.getAll(ctx, s.maxQueryResults())
+}
+
+// GetVehiclesByOwner returns the vehicles of an owner from the owner index, which also works on LevelDB
+func (s *SmartContract) GetVehiclesByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Vehicle, error) {
+	return vehicleFamily.byOwner(ctx, owner, s.maxQueryResults())
+}
+
+func (s *SmartContract) GetVehiclesByColor(ctx contractapi.TransactionContextInterface, clr string) ([]*Vehicle, error) {
+	return vehicleFamily.byColor(ctx, clr, s.maxQueryResults())
+}
+
+func (s *SmartContract) VehicleExists(ctx contractapi.TransactionContextInterface, id int) (bool, error) {
+	return vehicleFamily.exists(ctx, id)
+}
+
+func (s *SmartContract) ChangeVehicleColour(ctx contractapi.TransactionContextInterface, id int, clr string) error {
+	return vehicleFamily.changeColour(ctx, id, clr)
+}
+
+func (s *SmartContract) ChangeVehicleValue(ctx contractapi.TransactionContextInterface, id int, val string) error {
+	return vehicleFamily.changeValue(ctx, id, val)
+}
+
+// check applies the rules of vehicles once any of their details is given, as vehicles
+// created without details only carry the common attributes
+func (v *Vehicle) check(val *validator) {
+	if v.VIN == `` && v.Make == `` && v.Model == `` && v.Year == 0 && v.Mileage == 0 {
+		return
+	}
+
+	val.check(vinPattern.MatchString(v.VIN), `vin`, `vin %s is not a valid vehicle identification number`, v.VIN)
+	val.text(`make`, v.Make, maxTextLength)
+	val.text(`model`, v.Model, maxTextLength)
+	val.check(v.Year >= firstVehicleYear, `year`, `year %d precedes the first production vehicle`, v.Year)
+	val.check(v.Mileage >= 0, `mileage`, `mileage %d can not be negative`, v.Mileage)
+}
+
+func (v *Vehicle) id() int                  { return v.ID }
+func (v *Vehicle) setID(id int)             { v.ID = id }
+func (v *Vehicle) owner() string            { return v.Owner }
+func (v *Vehicle) setOwner(owner string)    { v.Owner = owner }
+func (v *Vehicle) color() string            { return v.Color }
+func (v *Vehicle) setColor(clr string)      { v.Color = clr }
+func (v *Vehicle) value() Money             { return v.Value }
+func (v *Vehicle) setValue(val Money)       { v.Value = val }
+func (v *Vehicle) setCreated(at, by string) { v.CreatedAt, v.CreatedBy = at, by }
+func (v *Vehicle) setUpdated(at, by string) { v.UpdatedAt, v.UpdatedBy = at, by }
+func (v *Vehicle) version() int             { return v.Version }
+func (v *Vehicle) setVersion(ver int)       { v.Version = ver }
+func (v *Vehicle) setDocType(kind string)   { v.DocType = kind }
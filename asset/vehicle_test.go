@@ -1,7 +1,6 @@
 package asset
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
@@ -12,7 +11,7 @@ import (
 )
 
 var (
-	testVehicle = Asset{Color: "brown", ID: 88, Owner: "Arnold", Value: 989}
+	testVehicle = Vehicle{Color: "brown", DocType: kindVehicle, ID: 88, Owner: "Arnold", Value: euros(989)}
 )
 
 func TestSmartContractCreateVehicle(t *testing.T) {
@@ -22,7 +21,7 @@ func TestSmartContractCreateVehicle(t *testing.T) {
 	in := marshalVehicle()
 	out := getVehicleState(stub, testVehicle.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -32,13 +31,14 @@ func TestSmartContractUpdateVehicle(t *testing.T) {
 	testCreateVehicle(stub, t)
 
 	testVehicle.Color = clrBlue
-	testVehicle.Value = 1500
+	testVehicle.Value = euros(1500)
 	testUpdateVehicle(stub, t)
+	testVehicle.Version = 2
 
 	in := marshalVehicle()
 	out := getVehicleState(stub, testVehicle.ID, t)
 
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -53,7 +53,7 @@ func TestSmartContractGetVehicle(t *testing.T) {
 	}
 
 	in := marshalVehicle()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -61,14 +61,16 @@ func TestSmartContractGetVehicle(t *testing.T) {
 func TestSmartContractGetAllVehicles(t *testing.T) {
 	stub := newMockStub()
 	testInitLedger(stub, t)
+	testCreateVehicle(stub, t)
 
+	// assets seeded by the ledger initialisation must not leak into the vehicle namespace
 	res := stub.MockInvoke(`2`, [][]byte{[]byte("GetAllVehicles")})
 	if res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
 	in := marshalVehicles()
-	if !bytes.Equal(in, res.Payload) {
+	if !sameRecords(in, res.Payload, t) {
 		t.Fatalf(errExpect, in, res.Payload)
 	}
 }
@@ -100,9 +102,10 @@ func TestSmartContractTransferVehicle(t *testing.T) {
 	}
 
 	testVehicle.Owner = ownrDavid
+	testVehicle.Version = 2
 	out := getVehicleState(stub, testVehicle.ID, t)
 	in := marshalVehicle()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -118,9 +121,10 @@ func TestSmartContractChangeVehicleColour(t *testing.T) {
 	}
 
 	testVehicle.Color = clrBrown
+	testVehicle.Version = 2
 	out := getVehicleState(stub, testVehicle.ID, t)
 	in := marshalVehicle()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
@@ -135,32 +139,104 @@ func TestSmartContractChangeVehicleValue(t *testing.T) {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 
-	testVehicle.Value = 888
+	testVehicle.Value = euros(888)
+	testVehicle.Version = 2
 	out := getVehicleState(stub, testVehicle.ID, t)
 	in := marshalVehicle()
-	if !bytes.Equal(in, out) {
+	if !sameRecords(in, out, t) {
 		t.Fatalf(errExpect, in, out)
 	}
 }
 
+func TestSmartContractCreateVehicleWithDetails(t *testing.T) {
+	stub := newMockStub()
+	v := Vehicle{Color: clrBlue, DocType: kindVehicle, ID: 101, Make: "Volvo", Mileage: 1200, Model: "XC40", Owner: ownrDavid, Value: euros(30000), Version: 1, VIN: "YV1XZ16G3M2123456", Year: 2021}
+
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateVehicleWithDetails"), []byte(v.Color), []byte(strconv.Itoa(v.ID)), []byte(v.Owner), []byte(v.Value.String()),
+		[]byte(v.VIN), []byte(v.Make), []byte(v.Model), []byte(strconv.Itoa(v.Year)), []byte(strconv.Itoa(v.Mileage)),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	v.Mileage, v.Version = 5400, 2
+	if res := stub.MockInvoke(`2`, [][]byte{
+		[]byte("UpdateVehicleDetails"), []byte(strconv.Itoa(v.ID)),
+		[]byte(v.VIN), []byte(v.Make), []byte(v.Model), []byte(strconv.Itoa(v.Year)), []byte(strconv.Itoa(v.Mileage)),
+	}); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	in, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal vehicle - %s", err.Error())
+	}
+
+	out := getVehicleState(stub, v.ID, t)
+	if !sameRecords(in, out, t) {
+		t.Fatalf(errExpect, in, out)
+	}
+}
+
+func TestSmartContractCreateVehicleWithInvalidVIN(t *testing.T) {
+	stub := newMockStub()
+	if res := stub.MockInvoke(`1`, [][]byte{
+		[]byte("CreateVehicleWithDetails"), []byte(clrBlue), []byte("102"), []byte(ownrDavid), []byte("100"),
+		[]byte("IO0000000000000QQ"), []byte("Volvo"), []byte("XC40"), []byte("2021"), []byte("0"),
+	}); res.Status == shim.OK {
+		t.Fatalf(`vehicle with an invalid vin should be rejected`)
+	}
+}
+
+func TestSmartContractGetLegacyVehicle(t *testing.T) {
+	stub := newMockStub()
+	legacy := Asset{Color: clrBlue, ID: 103, Owner: ownrDavid, Value: euros(700)}
+	byts, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatalf("failed to marshal asset - %s", err.Error())
+	}
+
+	stub.MockTransactionStart(`1`)
+	if err = stub.PutState(compositeKey(kindVehicle, legacy.ID, t), byts); err != nil {
+		t.Fatalf("failed to put legacy vehicle - %s", err.Error())
+	}
+	stub.MockTransactionEnd(`1`)
+
+	res := stub.MockInvoke(`2`, [][]byte{[]byte("GetVehicle"), []byte(strconv.Itoa(legacy.ID))})
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var v Vehicle
+	if err = json.Unmarshal(res.Payload, &v); err != nil {
+		t.Fatalf("failed to unmarshal vehicle - %s", err.Error())
+	}
+
+	if v.ID != legacy.ID || v.Owner != legacy.Owner || v.Value != legacy.Value {
+		t.Fatalf(errExpect, byts, res.Payload)
+	}
+}
+
 func testCreateVehicle(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`4`, [][]byte{
-		[]byte("CreateVehicle"), []byte(testVehicle.Color), []byte(strconv.Itoa(testVehicle.ID)), []byte(testVehicle.Owner), []byte(strconv.Itoa(testVehicle.Value)),
+		[]byte("CreateVehicle"), []byte(testVehicle.Color), []byte(strconv.Itoa(testVehicle.ID)), []byte(testVehicle.Owner), []byte(testVehicle.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
+
+	testVehicle.Version = 1
 }
 
 func testUpdateVehicle(stub *shimtest.MockStub, t *testing.T) {
 	if res := stub.MockInvoke(`5`, [][]byte{
-		[]byte("UpdateVehicle"), []byte(testVehicle.Color), []byte(strconv.Itoa(testVehicle.ID)), []byte(testVehicle.Owner), []byte(strconv.Itoa(testVehicle.Value)),
+		[]byte("UpdateVehicle"), []byte(testVehicle.Color), []byte(strconv.Itoa(testVehicle.ID)), []byte(testVehicle.Owner), []byte(testVehicle.Value.String()),
 	}); res.Status != shim.OK {
 		t.Fatalf(errOK, res.Status, res.Message)
 	}
 }
 
 func getVehicleState(stub *shimtest.MockStub, id int, t *testing.T) []byte {
-	out, err := stub.GetState(strconv.Itoa(id))
+	out, err := stub.GetState(compositeKey(kindVehicle, id, t))
 	if err != nil {
 		t.Fatalf("failed to retrieve asset info - %s", err.Error())
 	}
@@ -178,7 +254,7 @@ func marshalVehicle() []byte {
 }
 
 func marshalVehicles() []byte {
-	byts, err := json.Marshal(assets)
+	byts, err := json.Marshal([]Vehicle{testVehicle})
 	if err != nil {
 		log.Fatal(fmt.Sprintf("failed to marshal assets - %s", err.Error()))
 	}
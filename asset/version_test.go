@@ -0,0 +1,51 @@
+package asset
+
+import (
+	"encoding/json"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"testing"
+)
+
+func TestSmartContractUpdateAssetWithVersion(t *testing.T) {
+	stub := newQueryStub()
+	if res := stub.invoke(`1`, "CreateAsset", clrBlue, "5", ownrDavid, "100"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	if res := stub.invoke(`2`, "UpdateAssetWithVersion", kindAsset, clrBrown, "5", ownrDavid, "200", "1"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// the second writer still holds the first version
+	if res := stub.invoke(`3`, "UpdateAssetWithVersion", kindAsset, clrBlue, "5", ownrDavid, "300", "1"); DecodeError(res.Message).Code != CodeConflict {
+		t.Fatalf(errExpect, CodeConflict, res.Message)
+	}
+
+	if res := stub.invoke(`4`, "TransferAssetWithVersion", kindAsset, "5", "Arnold", "1"); DecodeError(res.Message).Code != CodeConflict {
+		t.Fatalf(errExpect, CodeConflict, res.Message)
+	}
+
+	if res := stub.invoke(`5`, "TransferAssetWithVersion", kindAsset, "5", "Arnold", "2"); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	// unconditional changes still move the version on
+	if res := stub.invoke(`6`, "ChangeAssetColour", "5", clrBlue); res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	res := stub.invoke(`7`, "GetAsset", "5")
+	if res.Status != shim.OK {
+		t.Fatalf(errOK, res.Status, res.Message)
+	}
+
+	var a Asset
+	if err := json.Unmarshal(res.Payload, &a); err != nil {
+		t.Fatalf("failed to unmarshal asset - %s", err.Error())
+	}
+
+	if a.Version != 4 || a.Owner != "Arnold" || a.Value != euros(200) {
+		in := Asset{Color: clrBlue, DocType: kindAsset, ID: 5, Owner: "Arnold", Value: euros(200), Version: 4}
+		t.Fatalf(errExpect, marshal(in, t), res.Payload)
+	}
+}
@@ -18,7 +18,7 @@ class AssetWorkload extends WorkloadModuleBase {
                 contractId: this.roundArguments.contractId,
                 contractFunction: 'CreateAsset',
                 invokerIdentity: 'peer1',
-                contractArguments: ['blue', assetID, 'Alex', '766'],
+                contractArguments: ['blue', assetID, '', '766'],
                 readOnly: false
             };
 
@@ -48,12 +48,15 @@ class AssetWorkload extends WorkloadModuleBase {
             const req = {
                 contractId: this.roundArguments.contractId,
                 contractFunction: 'DeleteAsset',
-                invokerIdentity: 'peer1',
+                invokerIdentity: 'admin',
                 contractArguments: [assetID],
                 readOnly: false
             };
 
             await this.sutAdapter.sendRequests(req);
+
+            // deleted records are archived, hence purged to leave the ledger as found
+            await this.sutAdapter.sendRequests({ ...req, contractFunction: 'PurgeAsset', contractArguments: ['asset', assetID] });
         }
     }
 }
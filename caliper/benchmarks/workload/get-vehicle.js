@@ -18,7 +18,7 @@ class VehicleWorkload extends WorkloadModuleBase {
                 contractId: this.roundArguments.contractId,
                 contractFunction: 'CreateVehicle',
                 invokerIdentity: 'peer1',
-                contractArguments: ['blue', vehicleID, 'Alex', '766'],
+                contractArguments: ['blue', vehicleID, '', '766'],
                 readOnly: false
             };
 
@@ -48,12 +48,15 @@ class VehicleWorkload extends WorkloadModuleBase {
             const req = {
                 contractId: this.roundArguments.contractId,
                 contractFunction: 'DeleteVehicle',
-                invokerIdentity: 'peer1',
+                invokerIdentity: 'admin',
                 contractArguments: [vehicleID],
                 readOnly: false
             };
 
             await this.sutAdapter.sendRequests(req);
+
+            // deleted records are archived, hence purged to leave the ledger as found
+            await this.sutAdapter.sendRequests({ ...req, contractFunction: 'PurgeAsset', contractArguments: ['vehicle', vehicleID] });
         }
     }
 }
@@ -20,7 +20,7 @@ class HouseWorkload extends WorkloadModuleBase {
             contractId: this.roundArguments.contractId,
             contractFunction: 'CreateHouse',
             invokerIdentity: 'peer1',
-            contractArguments: ['blue', assetID, 'Alex', '766'],
+            contractArguments: ['blue', assetID, '', '766'],
             readOnly: false,
         };
         //console.log(`RandID ${randId}: Creating asset ${assetID}`);
@@ -36,12 +36,15 @@ class HouseWorkload extends WorkloadModuleBase {
             const req = {
                 contractId: this.roundArguments.contractId,
                 contractFunction: 'DeleteHouse',
-                invokerIdentity: 'peer1',
+                invokerIdentity: 'admin',
                 contractArguments: [assetID],
                 readOnly: false
             };
 
             await this.sutAdapter.sendRequests(req);
+
+            // deleted records are archived, hence purged to leave the ledger as found
+            await this.sutAdapter.sendRequests({ ...req, contractFunction: 'PurgeAsset', contractArguments: ['house', assetID] });
         }
     }
 }
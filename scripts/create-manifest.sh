@@ -6,6 +6,12 @@ cc_ver=$3
 k8s_ver=$4
 cc_port=$5
 img_path=$6
+admin_msps=$7
+access_policy=$8
+max_query_results=$9
+max_batch_size=${10}
+token_minter_msp=${11}
+nft_base_uri=${12}
 
 fn="cc-$cc_name-$k8s_ver.yaml"
 
@@ -56,6 +62,18 @@ spec:
               value: \"$cc_id\"
             - name: CC_SERVER_ADDRESS
               value: \"$cc_name-$k8s_ver-pod:$cc_port\"
+            - name: CC_ADMIN_MSPS
+              value: \"$admin_msps\"
+            - name: CC_ACCESS_POLICY
+              value: '$access_policy'
+            - name: CC_MAX_QUERY_RESULTS
+              value: \"$max_query_results\"
+            - name: CC_MAX_BATCH_SIZE
+              value: \"$max_batch_size\"
+            - name: CC_TOKEN_MINTER_MSP
+              value: \"$token_minter_msp\"
+            - name: CC_NFT_BASE_URI
+              value: \"$nft_base_uri\"
           resources:
             limits:
               cpu: 500m
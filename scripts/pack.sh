@@ -17,6 +17,7 @@ echo -e "{
   \"label\": \"asset_$ver_label\"
 }" > metadata.json
 
-tar cfz code.tar.gz connection.json
+# couchdb indexes are read by the peer from the code package
+tar cfz code.tar.gz connection.json META-INF
 tar cfz "asset_v$ver_label.tar.gz" metadata.json code.tar.gz
 rm code.tar.gz
\ No newline at end of file
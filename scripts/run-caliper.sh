@@ -8,16 +8,22 @@ tls_cert_name=$5
 usr=$6
 peer_host=$7
 peer_port=$8
+admin_pvt_key_name=$9
+admin_pub_cert_name=${10}
 
 pvt_key_path="/hyperledger/caliper/workspace/peer/$pvt_key_name"
 pub_cert_path="/hyperledger/caliper/workspace/peer/$pub_cert_name"
 tls_cert_path="/hyperledger/caliper/workspace/peer/$tls_cert_name"
+admin_pvt_key_path="/hyperledger/caliper/workspace/peer/$admin_pvt_key_name"
+admin_pub_cert_path="/hyperledger/caliper/workspace/peer/$admin_pub_cert_name"
 peer_endpoint="$peer_host:$peer_port"
 
 sed -i "s+'<chan-name>'+'$chan_name'+g" caliper/network.yaml
 sed -i "s+'<org-msp>'+'$org_msp'+g" caliper/network.yaml
 sed -i "s+'<msp-private-key-file-path>'+'$pvt_key_path'+g" caliper/network.yaml
 sed -i "s+'<msp-public-cert-path>'+'$pub_cert_path'+g" caliper/network.yaml
+sed -i "s+'<admin-private-key-file-path>'+'$admin_pvt_key_path'+g" caliper/network.yaml
+sed -i "s+'<admin-public-cert-path>'+'$admin_pub_cert_path'+g" caliper/network.yaml
 sed -i "s+'<tls-root-cert-path>'+'$tls_cert_path'+g" caliper/network.yaml
 sed -i "s+'<user-name>'+'$usr'+g" caliper/network.yaml
 sed -i "s+'<peer-endpoint>'+'$peer_endpoint'+g" caliper/network.yaml
\ No newline at end of file
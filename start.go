@@ -1,18 +1,53 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/YasiruR/fabriK/chaincode/asset"
+	"git.unav.edu/daim/pliades/hfb/ccaas/asset"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/tryfix/log"
 	"os"
+	"strconv"
+	"strings"
 )
 
 func main() {
 	/* invoke chaincode as an external service */
 	log.Info(`starting chaincode as an external service`)
-	assetCC, err := contractapi.NewChaincode(&asset.SmartContract{})
+	contract := &asset.SmartContract{}
+	if maxResults := os.Getenv(`CC_MAX_QUERY_RESULTS`); maxResults != `` {
+		limit, err := strconv.Atoi(maxResults)
+		if err != nil {
+			log.Fatal(fmt.Sprintf(`invalid maximum number of query results - %v`, err))
+		}
+		contract.MaxQueryResults = limit
+	}
+
+	// imports are chunked by the batch size, hence resumed imports must keep the same size
+	if maxBatch := os.Getenv(`CC_MAX_BATCH_SIZE`); maxBatch != `` {
+		size, err := strconv.Atoi(maxBatch)
+		if err != nil {
+			log.Fatal(fmt.Sprintf(`invalid maximum batch size - %v`, err))
+		}
+		contract.MaxBatchSize = size
+	}
+
+	if adminMSPs := os.Getenv(`CC_ADMIN_MSPS`); adminMSPs != `` {
+		contract.AdminMSPs = strings.Split(adminMSPs, `,`)
+	}
+
+	if policy := os.Getenv(`CC_ACCESS_POLICY`); policy != `` {
+		if err := json.Unmarshal([]byte(policy), &contract.Policy); err != nil {
+			log.Fatal(fmt.Sprintf(`invalid access policy - %v`, err))
+		}
+	}
+
+	// the asset contract remains the default one, so token and NFT transactions are
+	// invoked as TokenContract:<name> and NFTContract:<name>
+	token := &asset.TokenContract{MinterMSP: os.Getenv(`CC_TOKEN_MINTER_MSP`)}
+	nft := &asset.NFTContract{BaseURI: os.Getenv(`CC_NFT_BASE_URI`)}
+	assetCC, err := contractapi.NewChaincode(contract, token, nft)
 	if err != nil {
 		log.Fatal(fmt.Sprintf(`creating chaincode failed - %v`, err))
 	}